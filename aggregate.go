@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bn254"
+	stdgroth16 "github.com/consensys/gnark/std/recursion/groth16"
+)
+
+// Curves of the aggregation. The inner EdDSA proofs are Groth16 proofs over
+// BN254, whose pairing the BW6-761 outer circuit computes with field
+// emulation, at about 1.5 million constraints per proof.
+const (
+	AggregationInnerCurve = ecc.BN254
+	AggregationOuterCurve = ecc.BW6_761
+)
+
+// AggregationCircuit verifies a fixed number of inner EdDSA Groth16 proofs,
+// so that a single outer proof attests to all of them. The inner verifying
+// key is a constant of the circuit, and the public inputs of every inner
+// proof are public inputs of the outer one.
+type AggregationCircuit struct {
+	Proofs    []stdgroth16.Proof[sw_bn254.G1Affine, sw_bn254.G2Affine]
+	Witnesses []stdgroth16.Witness[sw_bn254.ScalarField] `gnark:",public"`
+
+	vk stdgroth16.VerifyingKey[sw_bn254.G1Affine, sw_bn254.G2Affine, sw_bn254.GTEl] `gnark:"-"`
+}
+
+// NewAggregationCircuit returns the compilation template of a circuit
+// aggregating n proofs for innerCCS, as returned by SetupInner, under the
+// verifying key innerVK.
+func NewAggregationCircuit(innerCCS constraint.ConstraintSystem, innerVK groth16.VerifyingKey, n int) (*AggregationCircuit, error) {
+	vk, err := stdgroth16.ValueOfVerifyingKeyFixed[sw_bn254.G1Affine, sw_bn254.G2Affine, sw_bn254.GTEl](innerVK)
+	if err != nil {
+		return nil, fmt.Errorf("converting inner verifying key: %w", err)
+	}
+	circuit := &AggregationCircuit{
+		Proofs:    make([]stdgroth16.Proof[sw_bn254.G1Affine, sw_bn254.G2Affine], n),
+		Witnesses: make([]stdgroth16.Witness[sw_bn254.ScalarField], n),
+		vk:        vk,
+	}
+	for i := range n {
+		circuit.Proofs[i] = stdgroth16.PlaceholderProof[sw_bn254.G1Affine, sw_bn254.G2Affine](innerCCS)
+		circuit.Witnesses[i] = stdgroth16.PlaceholderWitness[sw_bn254.ScalarField](innerCCS)
+	}
+	return circuit, nil
+}
+
+// NewAggregationAssignment returns an assignment of the aggregation circuit
+// from inner proofs made by ProveInner and their public witnesses.
+func NewAggregationAssignment(proofs []groth16.Proof, publicWitnesses []witness.Witness) (*AggregationCircuit, error) {
+	if len(proofs) != len(publicWitnesses) {
+		return nil, fmt.Errorf("got %d proofs but %d public witnesses", len(proofs), len(publicWitnesses))
+	}
+	assignment := &AggregationCircuit{
+		Proofs:    make([]stdgroth16.Proof[sw_bn254.G1Affine, sw_bn254.G2Affine], len(proofs)),
+		Witnesses: make([]stdgroth16.Witness[sw_bn254.ScalarField], len(proofs)),
+	}
+	for i := range proofs {
+		var err error
+		assignment.Proofs[i], err = stdgroth16.ValueOfProof[sw_bn254.G1Affine, sw_bn254.G2Affine](proofs[i])
+		if err != nil {
+			return nil, fmt.Errorf("converting proof %d: %w", i, err)
+		}
+		assignment.Witnesses[i], err = stdgroth16.ValueOfWitness[sw_bn254.ScalarField](publicWitnesses[i])
+		if err != nil {
+			return nil, fmt.Errorf("converting public witness %d: %w", i, err)
+		}
+	}
+	return assignment, nil
+}
+
+// Define implements the circuit verifying every inner proof.
+func (circuit *AggregationCircuit) Define(api frontend.API) error {
+	verifier, err := stdgroth16.NewVerifier[sw_bn254.ScalarField, sw_bn254.G1Affine, sw_bn254.G2Affine, sw_bn254.GTEl](api)
+	if err != nil {
+		return fmt.Errorf("new verifier: %w", err)
+	}
+	for i := range circuit.Proofs {
+		// Unused message limbs are zero, which the incomplete formulas of the
+		// public input multi-scalar multiplication cannot handle
+		if err := verifier.AssertProof(circuit.vk, circuit.Proofs[i], circuit.Witnesses[i], stdgroth16.WithCompleteArithmetic()); err != nil {
+			return fmt.Errorf("asserting proof %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// SetupInner compiles the EdDSA circuit over AggregationInnerCurve and runs
+// its Groth16 setup.
+func SetupInner() (constraint.ConstraintSystem, groth16.ProvingKey, groth16.VerifyingKey, error) {
+	ccs, err := CompileCircuit(AggregationInnerCurve, backend.GROTH16)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	pk, vk, err := SetupGroth16(ccs)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return ccs, pk, vk, nil
+}
+
+// ProveInner proves assignment against the inner circuit and returns the
+// proof with its public witness, ready for NewAggregationAssignment. The
+// proof is made with the hash the outer circuit recomputes, so it must be
+// verified natively with VerifyInner rather than VerifyWithGroth16.
+func ProveInner(ccs constraint.ConstraintSystem, pk groth16.ProvingKey, assignment frontend.Circuit) (groth16.Proof, witness.Witness, error) {
+	fullWitness, err := frontend.NewWitness(assignment, ccs.Field())
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating witness: %w", err)
+	}
+	publicWitness, err := fullWitness.Public()
+	if err != nil {
+		return nil, nil, fmt.Errorf("extracting public witness: %w", err)
+	}
+
+	start := time.Now()
+	opt := stdgroth16.GetNativeProverOptions(AggregationOuterCurve.ScalarField(), AggregationInnerCurve.ScalarField())
+	proof, err := groth16Prove(ccs, pk, fullWitness, opt)
+	if err != nil {
+		return nil, nil, wrapProveError("groth16", err)
+	}
+	logger().Info(EventProofGenerated, "backend", "groth16", "duration", time.Since(start))
+	return proof, publicWitness, nil
+}
+
+// VerifyInner checks natively a proof made by ProveInner.
+func VerifyInner(vk groth16.VerifyingKey, proof groth16.Proof, publicWitness witness.Witness) error {
+	start := time.Now()
+	opt := stdgroth16.GetNativeVerifierOptions(AggregationOuterCurve.ScalarField(), AggregationInnerCurve.ScalarField())
+	err := groth16Verify(proof, vk, publicWitness, opt)
+	logVerify("groth16", start, err)
+	if err != nil {
+		return fmt.Errorf("groth16 verify: %w", err)
+	}
+	return nil
+}
+
+// CompileAggregation compiles the circuit aggregating n proofs for innerCCS
+// over AggregationOuterCurve, for Groth16.
+func CompileAggregation(innerCCS constraint.ConstraintSystem, innerVK groth16.VerifyingKey, n int) (constraint.ConstraintSystem, error) {
+	circuit, err := NewAggregationCircuit(innerCCS, innerVK, n)
+	if err != nil {
+		return nil, err
+	}
+	return compileCircuit(AggregationOuterCurve, backend.GROTH16, circuit)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"crypto/rand"
+	"flag"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	cryptoeddsa "github.com/consensys/gnark-crypto/signature/eddsa"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/test"
+)
+
+var fullAggregation = flag.Bool("aggregate", false, "also prove and verify the outer aggregation proof, whose BW6-761 setup over 3 million constraints needs much more memory than the other tests")
+
+func TestAggregation(t *testing.T) {
+	if testing.Short() {
+		t.Skip("solving the aggregation circuit takes half a minute")
+	}
+	const n = 2
+	innerCCS, innerPK, innerVK, err := SetupInner()
+	if err != nil {
+		t.Fatal("Error in inner setup:", err)
+	}
+
+	proofs := make([]groth16.Proof, n)
+	publicWitnesses := make([]witness.Witness, n)
+	for i := range n {
+		privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+		if err != nil {
+			t.Fatal("Error generating private key:", err)
+		}
+		msg := []byte{0xde, 0xad, 0xf0, byte(i)}
+		sig, err := SignMessage(privateKey, msg, DefaultMessageLimbs)
+		if err != nil {
+			t.Fatal("Error signing message:", err)
+		}
+		assignment, err := NewAssignment(privateKey.Public().Bytes(), sig, msg, DefaultMessageLimbs)
+		if err != nil {
+			t.Fatal("Error creating assignment:", err)
+		}
+		proofs[i], publicWitnesses[i], err = ProveInner(innerCCS, innerPK, assignment)
+		if err != nil {
+			t.Fatal("Error proving inner proof:", err)
+		}
+		if err := VerifyInner(innerVK, proofs[i], publicWitnesses[i]); err != nil {
+			t.Fatal("Error verifying inner proof:", err)
+		}
+	}
+
+	circuit, err := NewAggregationCircuit(innerCCS, innerVK, n)
+	if err != nil {
+		t.Fatal("Error creating aggregation circuit:", err)
+	}
+	assignment, err := NewAggregationAssignment(proofs, publicWitnesses)
+	if err != nil {
+		t.Fatal("Error creating aggregation assignment:", err)
+	}
+	if err := test.IsSolved(circuit, assignment, AggregationOuterCurve.ScalarField()); err != nil {
+		t.Fatal("Expected the inner proofs to satisfy the aggregation circuit:", err)
+	}
+
+	// Swapping the statements of the two proofs breaks the aggregate
+	swappedWitnesses := []witness.Witness{publicWitnesses[1], publicWitnesses[0]}
+	swapped, err := NewAggregationAssignment(proofs, swappedWitnesses)
+	if err != nil {
+		t.Fatal("Error creating aggregation assignment:", err)
+	}
+	if err := test.IsSolved(circuit, swapped, AggregationOuterCurve.ScalarField()); err == nil {
+		t.Fatal("Expected swapped statements not to satisfy the aggregation circuit")
+	}
+
+	if !*fullAggregation {
+		t.Log("Skipping the outer proof; run with -aggregate to prove and verify it")
+		return
+	}
+	outerCCS, err := CompileAggregation(innerCCS, innerVK, n)
+	if err != nil {
+		t.Fatal("Error compiling aggregation circuit:", err)
+	}
+	pk, vk, err := SetupGroth16(outerCCS)
+	if err != nil {
+		t.Fatal("Error in outer setup:", err)
+	}
+	proof, err := ProveWithGroth16(outerCCS, pk, assignment)
+	if err != nil {
+		t.Fatal("Error proving aggregation:", err)
+	}
+	if err := VerifyWithGroth16(outerCCS, vk, proof, assignment); err != nil {
+		t.Fatal("Error verifying aggregation:", err)
+	}
+	if err := VerifyWithGroth16(outerCCS, vk, proof, swapped); err == nil {
+		t.Fatal("Expected the aggregate proof to fail for swapped statements")
+	}
+}
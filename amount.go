@@ -0,0 +1,116 @@
+package main
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark-crypto/signature"
+	"github.com/consensys/gnark/frontend"
+	tedwards "github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/rangecheck"
+	"github.com/consensys/gnark/std/signature/eddsa"
+)
+
+// AmountBits is the width of the amounts AmountEdDSACircuit accepts.
+const AmountBits = 64
+
+// amountDomain is the domain whose DomainTag AmountEdDSACircuit absorbs
+// first, so that its signatures verify in no other circuit with the same
+// layout of field elements.
+const amountDomain = "eddsa-gnark amount"
+
+// AmountEdDSACircuit verifies a signature over H(tag || amount || message),
+// the MiMC digest of the tag of amountDomain, Amount and the Message limbs,
+// and checks with std/rangecheck that Amount lies in [0, 2^AmountBits). A
+// valid signature over a larger amount, which a field element can hold,
+// still fails.
+type AmountEdDSACircuit struct {
+	PublicKey eddsa.PublicKey     `gnark:",public"`
+	Signature eddsa.Signature     `gnark:",public"`
+	Amount    frontend.Variable   `gnark:",public"`
+	Message   []frontend.Variable `gnark:",public"`
+
+	// UseCommitment verifies the signature with table lookups checked
+	// against a commitment to the witness. See EdDSACircuit.UseCommitment.
+	UseCommitment bool `gnark:"-"`
+}
+
+// NewAmountCircuit returns an amount circuit whose Message holds nbLimbs
+// limbs, for use both as the compilation template and as an assignment.
+func NewAmountCircuit(nbLimbs int) *AmountEdDSACircuit {
+	return &AmountEdDSACircuit{Message: make([]frontend.Variable, nbLimbs)}
+}
+
+// HashAmountMessage returns the MiMC digest of the amount domain tag,
+// amount and msg split into nbLimbs limbs, matching AmountEdDSACircuit.
+// amount must be a BN254 scalar field element, but need not fit in
+// AmountBits bits, so that out-of-range amounts can be signed and shown to
+// fail.
+func HashAmountMessage(amount *big.Int, msg []byte, nbLimbs int) ([]byte, error) {
+	if amount == nil || amount.Sign() < 0 || amount.Cmp(ecc.BN254.ScalarField()) >= 0 {
+		return nil, errors.New("amount must be a field element")
+	}
+	tag, err := DomainTag(amountDomain)
+	if err != nil {
+		return nil, err
+	}
+	elements, err := messageElements(msg, nbLimbs)
+	if err != nil {
+		return nil, err
+	}
+	elements = append([]*big.Int{tag.BigInt(new(big.Int)), amount}, elements...)
+	return hashElements(mimc.NewMiMC(), ecc.BN254, elements)
+}
+
+// SignAmountMessage signs the digest computed by HashAmountMessage with a
+// BN254 private key.
+func SignAmountMessage(priv signature.Signer, amount *big.Int, msg []byte, nbLimbs int) ([]byte, error) {
+	digest, err := HashAmountMessage(amount, msg, nbLimbs)
+	if err != nil {
+		return nil, err
+	}
+	return priv.Sign(digest, mimc.NewMiMC())
+}
+
+// NewAmountAssignment returns an assignment of the amount circuit with
+// nbLimbs message limbs for a BN254 public key and a signature produced by
+// SignAmountMessage.
+func NewAmountAssignment(pubKey, sig []byte, amount *big.Int, msg []byte, nbLimbs int) (*AmountEdDSACircuit, error) {
+	limbs, err := MessageLimbs(msg, nbLimbs)
+	if err != nil {
+		return nil, err
+	}
+	assignment := &AmountEdDSACircuit{Amount: amount, Message: limbs}
+	if err := assignEncodings(twistededwards.BN254, &assignment.PublicKey, &assignment.Signature, pubKey, sig); err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
+
+// Define implements the circuit for EdDSA verification of a message carrying
+// a bounded amount
+func (circuit *AmountEdDSACircuit) Define(api frontend.API) error {
+	curve, err := tedwards.NewEdCurve(api, twistededwards.BN254)
+	if err != nil {
+		return err
+	}
+	curve = commitCurve(curve, circuit.UseCommitment)
+	hash, err := newMiMCFieldHasher(api)
+	if err != nil {
+		return err
+	}
+
+	tag, err := DomainTag(amountDomain)
+	if err != nil {
+		return err
+	}
+
+	rangecheck.New(api).Check(circuit.Amount, AmountBits)
+
+	// The domain tag and the amount are absorbed before the message limbs
+	fields := append([]frontend.Variable{tag, circuit.Amount}, circuit.Message...)
+	return verifyMessageSignature(curve, hash, circuit.PublicKey, circuit.Signature, fields)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	cryptoeddsa "github.com/consensys/gnark-crypto/signature/eddsa"
+	"github.com/consensys/gnark/test"
+)
+
+func TestAmountEdDSACircuit(t *testing.T) {
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal("Error creating private key:", err)
+	}
+	pubKey := privateKey.Public().Bytes()
+	msg := []byte{0xde, 0xad, 0xf0, 0x0d}
+
+	assert := test.NewAssert(t)
+	maxAmount := new(big.Int).Lsh(big.NewInt(1), AmountBits)
+	maxAmount.Sub(maxAmount, big.NewInt(1))
+	for _, amount := range []*big.Int{big.NewInt(0), big.NewInt(250_000_000), maxAmount} {
+		sig, err := SignAmountMessage(privateKey, amount, msg, DefaultMessageLimbs)
+		if err != nil {
+			t.Fatal("Error signing message:", err)
+		}
+		assignment, err := NewAmountAssignment(pubKey, sig, amount, msg, DefaultMessageLimbs)
+		if err != nil {
+			t.Fatal("Error creating assignment:", err)
+		}
+		for _, circuit := range newAmountCircuits() {
+			assert.SolvingSucceeded(circuit, assignment, test.WithCurves(ecc.BN254))
+		}
+	}
+
+	// A validly signed amount of 2^64 is out of range
+	tooLarge := new(big.Int).Lsh(big.NewInt(1), AmountBits)
+	sig, err := SignAmountMessage(privateKey, tooLarge, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error signing message:", err)
+	}
+	assignment, err := NewAmountAssignment(pubKey, sig, tooLarge, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+	for _, circuit := range newAmountCircuits() {
+		assert.SolvingFailed(circuit, assignment, test.WithCurves(ecc.BN254))
+	}
+}
+
+// newAmountCircuits returns the amount circuit without and with
+// UseCommitment.
+func newAmountCircuits() []*AmountEdDSACircuit {
+	committed := NewAmountCircuit(DefaultMessageLimbs)
+	committed.UseCommitment = true
+	return []*AmountEdDSACircuit{NewAmountCircuit(DefaultMessageLimbs), committed}
+}
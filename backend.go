@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	plonkbls12377 "github.com/consensys/gnark/backend/plonk/bls12-377"
+	plonkbls12381 "github.com/consensys/gnark/backend/plonk/bls12-381"
+	plonkbls24315 "github.com/consensys/gnark/backend/plonk/bls24-315"
+	plonkbn254 "github.com/consensys/gnark/backend/plonk/bn254"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+)
+
+// ProvingKey, VerifyingKey and Proof are the keys and proofs of any Backend.
+// Each Backend only accepts the ones it produced: the Groth16 backend takes
+// groth16 keys and proofs, and the PLONK backend plonk ones.
+type (
+	ProvingKey interface {
+		io.WriterTo
+		io.ReaderFrom
+	}
+	VerifyingKey interface {
+		io.WriterTo
+		io.ReaderFrom
+	}
+	Proof interface {
+		io.WriterTo
+		io.ReaderFrom
+	}
+)
+
+// Backend is a proving system a Verifier proves and verifies with.
+type Backend interface {
+	// ID selects the constraint system builder the circuit is compiled
+	// with.
+	ID() backend.ID
+
+	// Setup generates the proving and verifying keys for ccs.
+	Setup(ccs constraint.ConstraintSystem) (ProvingKey, VerifyingKey, error)
+
+	// Prove proves assignment against ccs with pk. An unsatisfied assignment
+	// fails with ErrSignatureInvalid.
+	Prove(ccs constraint.ConstraintSystem, pk ProvingKey, assignment frontend.Circuit) (Proof, error)
+
+	// Verify checks proof against vk and publicWitness.
+	Verify(vk VerifyingKey, proof Proof, publicWitness witness.Witness) error
+
+	// NewProof returns an empty proof over curve for ReadFrom to fill, as
+	// Prove writes them.
+	NewProof(curve ecc.ID) Proof
+}
+
+// Groth16Backend is the Groth16 Backend, the default of a Verifier.
+type Groth16Backend struct{}
+
+// ID returns backend.GROTH16.
+func (Groth16Backend) ID() backend.ID {
+	return backend.GROTH16
+}
+
+// Setup runs SetupGroth16.
+func (Groth16Backend) Setup(ccs constraint.ConstraintSystem) (ProvingKey, VerifyingKey, error) {
+	return SetupGroth16(ccs)
+}
+
+// Prove runs ProveWithGroth16.
+func (Groth16Backend) Prove(ccs constraint.ConstraintSystem, pk ProvingKey, assignment frontend.Circuit) (Proof, error) {
+	groth16PK, ok := pk.(groth16.ProvingKey)
+	if !ok {
+		return nil, fmt.Errorf("groth16 prove: proving key is a %T", pk)
+	}
+	return ProveWithGroth16(ccs, groth16PK, assignment)
+}
+
+// Verify checks a Groth16 proof.
+func (Groth16Backend) Verify(vk VerifyingKey, proof Proof, publicWitness witness.Witness) error {
+	groth16VK, ok := vk.(groth16.VerifyingKey)
+	if !ok {
+		return fmt.Errorf("groth16 verify: verifying key is a %T", vk)
+	}
+	groth16Proof, ok := proof.(groth16.Proof)
+	if !ok {
+		return fmt.Errorf("groth16 verify: proof is a %T", proof)
+	}
+	return verifyPublicWitness(groth16VK, groth16Proof, publicWitness)
+}
+
+// NewProof returns an empty groth16 proof.
+func (Groth16Backend) NewProof(curve ecc.ID) Proof {
+	return groth16.NewProof(curve)
+}
+
+// PlonkBackend is the PLONK Backend. Its setup uses SRS, the KZG SRS from a
+// trusted setup ceremony as loaded by LoadKZGSRS, or an unsafe test SRS when
+// SRS is nil. See SetupPlonk.
+type PlonkBackend struct {
+	SRS kzg.SRS
+}
+
+// ID returns backend.PLONK.
+func (PlonkBackend) ID() backend.ID {
+	return backend.PLONK
+}
+
+// Setup runs SetupPlonkWithSRS, or SetupPlonk without an SRS.
+func (b PlonkBackend) Setup(ccs constraint.ConstraintSystem) (ProvingKey, VerifyingKey, error) {
+	if b.SRS == nil {
+		return SetupPlonk(ccs)
+	}
+	return SetupPlonkWithSRS(ccs, b.SRS)
+}
+
+// Prove runs ProveWithPlonk.
+func (PlonkBackend) Prove(ccs constraint.ConstraintSystem, pk ProvingKey, assignment frontend.Circuit) (Proof, error) {
+	plonkPK, ok := pk.(plonk.ProvingKey)
+	if !ok {
+		return nil, fmt.Errorf("plonk prove: proving key is a %T", pk)
+	}
+	return ProveWithPlonk(ccs, plonkPK, assignment)
+}
+
+// Verify checks a PLONK proof.
+func (PlonkBackend) Verify(vk VerifyingKey, proof Proof, publicWitness witness.Witness) error {
+	// Groth16 keys and proofs also have the methods of the plonk interfaces,
+	// and plonk.Verify panics on them, so the concrete types are checked
+	var ok bool
+	switch proof.(type) {
+	case *plonkbn254.Proof:
+		_, ok = vk.(*plonkbn254.VerifyingKey)
+	case *plonkbls12381.Proof:
+		_, ok = vk.(*plonkbls12381.VerifyingKey)
+	case *plonkbls12377.Proof:
+		_, ok = vk.(*plonkbls12377.VerifyingKey)
+	case *plonkbls24315.Proof:
+		_, ok = vk.(*plonkbls24315.VerifyingKey)
+	}
+	if !ok {
+		return fmt.Errorf("plonk verify: %T is not a proof for verifying key %T", proof, vk)
+	}
+	return verifyPlonkPublicWitness(vk.(plonk.VerifyingKey), proof.(plonk.Proof), publicWitness)
+}
+
+// NewProof returns an empty plonk proof.
+func (PlonkBackend) NewProof(curve ecc.ID) Proof {
+	return plonk.NewProof(curve)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+)
+
+// stubBackend proves nothing: its keys and proofs are empty buffers, and
+// Verify accepts any proof it made. It records how often it was called.
+type stubBackend struct {
+	setups, proofs, verifications int
+}
+
+func (b *stubBackend) ID() backend.ID {
+	return backend.GROTH16
+}
+
+func (b *stubBackend) Setup(ccs constraint.ConstraintSystem) (ProvingKey, VerifyingKey, error) {
+	b.setups++
+	return new(bytes.Buffer), new(bytes.Buffer), nil
+}
+
+func (b *stubBackend) Prove(ccs constraint.ConstraintSystem, pk ProvingKey, assignment frontend.Circuit) (Proof, error) {
+	b.proofs++
+	return bytes.NewBufferString("stub proof"), nil
+}
+
+func (b *stubBackend) Verify(vk VerifyingKey, proof Proof, publicWitness witness.Witness) error {
+	b.verifications++
+	if buf, ok := proof.(*bytes.Buffer); !ok || buf.String() != "stub proof" {
+		return errors.New("not a stub proof")
+	}
+	return nil
+}
+
+func (b *stubBackend) NewProof(curve ecc.ID) Proof {
+	return new(bytes.Buffer)
+}
+
+func TestVerifierStubBackend(t *testing.T) {
+	stub := new(stubBackend)
+	v, err := NewVerifierWithConfig(ecc.BN254, VerifierConfig{Backend: stub})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKeys, sigs, msgs := signBatch(t, 1)
+	pubKey, sig, msg := pubKeys[0], sigs[0], msgs[0]
+
+	proof, err := v.Prove(pubKey, sig, msg)
+	if err != nil {
+		t.Fatal("Error proving:", err)
+	}
+	if err := v.Verify(proof, pubKey, sig, msg); err != nil {
+		t.Fatal("Error verifying:", err)
+	}
+	if err := v.Verify(bytes.NewBufferString("forged"), pubKey, sig, msg); err == nil {
+		t.Fatal("Expected the stub backend to reject a proof it did not make")
+	}
+	if stub.setups != 1 || stub.proofs != 1 || stub.verifications != 2 {
+		t.Fatalf("Backend called %d/%d/%d times for setup/prove/verify, want 1/1/2", stub.setups, stub.proofs, stub.verifications)
+	}
+
+	// Inputs are still checked before reaching the backend
+	if _, err := v.Prove(pubKey, sig[:len(sig)-1], msg); err == nil {
+		t.Fatal("Expected an error proving a truncated signature")
+	}
+	if stub.proofs != 1 {
+		t.Fatal("Expected a truncated signature not to reach the backend")
+	}
+}
+
+func TestVerifierPlonkBackend(t *testing.T) {
+	v, err := NewVerifierWithConfig(ecc.BN254, VerifierConfig{Backend: PlonkBackend{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKeys, sigs, msgs := signBatch(t, 1)
+	pubKey, sig, msg := pubKeys[0], sigs[0], msgs[0]
+
+	proof, err := v.Prove(pubKey, sig, msg)
+	if err != nil {
+		t.Fatal("Error proving:", err)
+	}
+	if err := v.Verify(proof, pubKey, sig, msg); err != nil {
+		t.Fatal("Error verifying:", err)
+	}
+	if err := v.Verify(proof, pubKey, sig, []byte{0xba, 0xd0}); err == nil {
+		t.Fatal("Expected an error verifying another message")
+	}
+
+	// A Groth16 proof is rejected rather than misread
+	_, _, _, _, groth16Proof := newProvedVerifier(t)
+	if err := v.Verify(groth16Proof, pubKey, sig, msg); err == nil {
+		t.Fatal("Expected an error verifying a Groth16 proof with PLONK")
+	}
+}
@@ -0,0 +1,366 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	tedwards "github.com/consensys/gnark/std/algebra/native/twistededwards"
+	stdhash "github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/signature/eddsa"
+)
+
+// BatchEdDSACircuit verifies a fixed number of EdDSA signatures in a single
+// circuit, amortizing the proof overhead across all of them. Slot i holds
+// the public key, signature and message limbs of the i-th signature.
+type BatchEdDSACircuit struct {
+	PublicKeys []eddsa.PublicKey     `gnark:",public"`
+	Signatures []eddsa.Signature     `gnark:",public"`
+	Messages   [][]frontend.Variable `gnark:",public"`
+}
+
+// NewBatchCircuit returns a batch circuit with n slots, each holding a
+// message of DefaultMessageLimbs limbs.
+func NewBatchCircuit(n int) *BatchEdDSACircuit {
+	circuit := &BatchEdDSACircuit{
+		PublicKeys: make([]eddsa.PublicKey, n),
+		Signatures: make([]eddsa.Signature, n),
+		Messages:   make([][]frontend.Variable, n),
+	}
+	for i := range circuit.Messages {
+		circuit.Messages[i] = make([]frontend.Variable, DefaultMessageLimbs)
+	}
+	return circuit
+}
+
+// NewBatchAssignment returns an assignment of a batch circuit with one slot
+// per signature. pubKeys, sigs and msgs must have the same length.
+func NewBatchAssignment(pubKeys, sigs, msgs [][]byte) (*BatchEdDSACircuit, error) {
+	if len(sigs) != len(pubKeys) || len(msgs) != len(pubKeys) {
+		return nil, fmt.Errorf("batch has %d public keys, %d signatures and %d messages", len(pubKeys), len(sigs), len(msgs))
+	}
+
+	assignment := NewBatchCircuit(len(pubKeys))
+	for i := range pubKeys {
+		limbs, err := MessageLimbs(msgs[i], DefaultMessageLimbs)
+		if err != nil {
+			return nil, fmt.Errorf("message %d: %w", i, err)
+		}
+		assignment.Messages[i] = limbs
+		if err := assignEncodings(twistededwards.BN254, &assignment.PublicKeys[i], &assignment.Signatures[i], pubKeys[i], sigs[i]); err != nil {
+			return nil, fmt.Errorf("signature %d: %w", i, err)
+		}
+	}
+	return assignment, nil
+}
+
+// Define implements the circuit for batch EdDSA signature verification
+func (circuit *BatchEdDSACircuit) Define(api frontend.API) error {
+	if len(circuit.Signatures) != len(circuit.PublicKeys) || len(circuit.Messages) != len(circuit.PublicKeys) {
+		return fmt.Errorf("batch has %d public keys, %d signatures and %d messages", len(circuit.PublicKeys), len(circuit.Signatures), len(circuit.Messages))
+	}
+
+	curve, err := tedwards.NewEdCurve(api, twistededwards.BN254)
+	if err != nil {
+		return err
+	}
+	hash, err := newMiMCFieldHasher(api)
+	if err != nil {
+		return err
+	}
+
+	for i := range circuit.PublicKeys {
+		if err := verifyMessageSignature(curve, hash, circuit.PublicKeys[i], circuit.Signatures[i], circuit.Messages[i]); err != nil {
+			return fmt.Errorf("signature %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// MixedHashBatchEdDSACircuit is BatchEdDSACircuit for signatures made under
+// different hashes: slot i was signed with SignMessageWith under the HashID
+// Hashes[i]. Every slot is hashed under each of SupportedHashes and the
+// digest selected by its HashID is verified, so each slot costs as much as
+// all the hashes together.
+type MixedHashBatchEdDSACircuit struct {
+	PublicKeys []eddsa.PublicKey     `gnark:",public"`
+	Signatures []eddsa.Signature     `gnark:",public"`
+	Messages   [][]frontend.Variable `gnark:",public"`
+	Hashes     []frontend.Variable   `gnark:",public"`
+}
+
+// NewMixedHashBatchCircuit returns a mixed hash batch circuit with n slots,
+// each holding a message of DefaultMessageLimbs limbs.
+func NewMixedHashBatchCircuit(n int) *MixedHashBatchEdDSACircuit {
+	batch := NewBatchCircuit(n)
+	return &MixedHashBatchEdDSACircuit{
+		PublicKeys: batch.PublicKeys,
+		Signatures: batch.Signatures,
+		Messages:   batch.Messages,
+		Hashes:     make([]frontend.Variable, n),
+	}
+}
+
+// NewMixedHashBatchAssignment returns an assignment of a mixed hash batch
+// circuit with one slot per signature, where sigs[i] was made under
+// hashes[i]. hashes, pubKeys, sigs and msgs must have the same length.
+func NewMixedHashBatchAssignment(hashes []HashID, pubKeys, sigs, msgs [][]byte) (*MixedHashBatchEdDSACircuit, error) {
+	if len(hashes) != len(pubKeys) {
+		return nil, fmt.Errorf("batch has %d hashes and %d public keys", len(hashes), len(pubKeys))
+	}
+	batch, err := NewBatchAssignment(pubKeys, sigs, msgs)
+	if err != nil {
+		return nil, err
+	}
+
+	assignment := &MixedHashBatchEdDSACircuit{
+		PublicKeys: batch.PublicKeys,
+		Signatures: batch.Signatures,
+		Messages:   batch.Messages,
+		Hashes:     make([]frontend.Variable, len(hashes)),
+	}
+	for i, id := range hashes {
+		if _, err := NewHashFunc(id); err != nil {
+			return nil, fmt.Errorf("signature %d: %w", i, err)
+		}
+		assignment.Hashes[i] = int(id)
+	}
+	return assignment, nil
+}
+
+// Define implements the circuit for batch EdDSA signature verification under
+// mixed hashes
+func (circuit *MixedHashBatchEdDSACircuit) Define(api frontend.API) error {
+	n := len(circuit.PublicKeys)
+	if len(circuit.Signatures) != n || len(circuit.Messages) != n || len(circuit.Hashes) != n {
+		return fmt.Errorf("batch has %d public keys, %d signatures, %d messages and %d hashes", n, len(circuit.Signatures), len(circuit.Messages), len(circuit.Hashes))
+	}
+
+	curve, err := tedwards.NewEdCurve(api, twistededwards.BN254)
+	if err != nil {
+		return err
+	}
+	hashers := make([]stdhash.FieldHasher, len(SupportedHashes))
+	for _, id := range SupportedHashes {
+		hashFunc, err := NewHashFunc(id)
+		if err != nil {
+			return err
+		}
+		if hashers[id], err = hashFunc.Circuit(api); err != nil {
+			return err
+		}
+	}
+
+	for i := range circuit.PublicKeys {
+		hash := newSelectedFieldHasher(api, circuit.Hashes[i], hashers)
+		if err := verifyMessageSignature(curve, hash, circuit.PublicKeys[i], circuit.Signatures[i], circuit.Messages[i]); err != nil {
+			return fmt.Errorf("signature %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// SignerBatchEdDSACircuit verifies a fixed number of messages all signed by
+// the same key. Unlike BatchEdDSACircuit, the public key is a single input,
+// so the proof shows that one signer produced every signature.
+type SignerBatchEdDSACircuit struct {
+	PublicKey  eddsa.PublicKey       `gnark:",public"`
+	Signatures []eddsa.Signature     `gnark:",public"`
+	Messages   [][]frontend.Variable `gnark:",public"`
+}
+
+// NewSignerBatchCircuit returns a single-signer batch circuit with n slots,
+// each holding a message of DefaultMessageLimbs limbs.
+func NewSignerBatchCircuit(n int) *SignerBatchEdDSACircuit {
+	batch := NewBatchCircuit(n)
+	return &SignerBatchEdDSACircuit{
+		Signatures: batch.Signatures,
+		Messages:   batch.Messages,
+	}
+}
+
+// NewSignerBatchAssignment returns an assignment of a single-signer batch
+// circuit with one slot per signature of pubKey. sigs and msgs must have the
+// same, non-zero, length.
+func NewSignerBatchAssignment(pubKey []byte, sigs, msgs [][]byte) (*SignerBatchEdDSACircuit, error) {
+	if len(sigs) == 0 || len(msgs) != len(sigs) {
+		return nil, fmt.Errorf("batch has %d signatures and %d messages", len(sigs), len(msgs))
+	}
+
+	assignment := NewSignerBatchCircuit(len(sigs))
+	for i := range sigs {
+		limbs, err := MessageLimbs(msgs[i], DefaultMessageLimbs)
+		if err != nil {
+			return nil, fmt.Errorf("message %d: %w", i, err)
+		}
+		assignment.Messages[i] = limbs
+		if err := assignEncodings(twistededwards.BN254, &assignment.PublicKey, &assignment.Signatures[i], pubKey, sigs[i]); err != nil {
+			return nil, fmt.Errorf("signature %d: %w", i, err)
+		}
+	}
+	return assignment, nil
+}
+
+// Define implements the circuit for single-signer batch EdDSA signature
+// verification
+func (circuit *SignerBatchEdDSACircuit) Define(api frontend.API) error {
+	if len(circuit.Messages) != len(circuit.Signatures) {
+		return fmt.Errorf("batch has %d signatures and %d messages", len(circuit.Signatures), len(circuit.Messages))
+	}
+
+	curve, err := tedwards.NewEdCurve(api, twistededwards.BN254)
+	if err != nil {
+		return err
+	}
+	hash, err := newMiMCFieldHasher(api)
+	if err != nil {
+		return err
+	}
+
+	for i := range circuit.Signatures {
+		if err := verifyMessageSignature(curve, hash, circuit.PublicKey, circuit.Signatures[i], circuit.Messages[i]); err != nil {
+			return fmt.Errorf("signature %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// PaddedBatchEdDSACircuit verifies up to a fixed number of EdDSA signatures.
+// Slots whose Enabled flag is 0 are skipped: their contents are replaced
+// in-circuit by a fixed padding signature, so they may hold anything. Count
+// is the number of enabled slots.
+type PaddedBatchEdDSACircuit struct {
+	PublicKeys []eddsa.PublicKey     `gnark:",public"`
+	Signatures []eddsa.Signature     `gnark:",public"`
+	Messages   [][]frontend.Variable `gnark:",public"`
+	Enabled    []frontend.Variable   `gnark:",public"`
+	Count      frontend.Variable     `gnark:",public"`
+}
+
+// NewPaddedBatchCircuit returns a padded batch circuit with n slots, each
+// holding a message of DefaultMessageLimbs limbs.
+func NewPaddedBatchCircuit(n int) *PaddedBatchEdDSACircuit {
+	batch := NewBatchCircuit(n)
+	return &PaddedBatchEdDSACircuit{
+		PublicKeys: batch.PublicKeys,
+		Signatures: batch.Signatures,
+		Messages:   batch.Messages,
+		Enabled:    make([]frontend.Variable, n),
+	}
+}
+
+// NewPaddedBatchAssignment returns an assignment of a padded batch circuit
+// with n slots, the first len(pubKeys) of which hold the given signatures
+// and are enabled. The remaining slots are disabled and zero.
+func NewPaddedBatchAssignment(n int, pubKeys, sigs, msgs [][]byte) (*PaddedBatchEdDSACircuit, error) {
+	if len(pubKeys) > n {
+		return nil, fmt.Errorf("batch of %d signatures exceeds the %d slots", len(pubKeys), n)
+	}
+	batch, err := NewBatchAssignment(pubKeys, sigs, msgs)
+	if err != nil {
+		return nil, err
+	}
+
+	assignment := NewPaddedBatchCircuit(n)
+	copy(assignment.PublicKeys, batch.PublicKeys)
+	copy(assignment.Signatures, batch.Signatures)
+	for i := range n {
+		if i < len(pubKeys) {
+			assignment.Messages[i] = batch.Messages[i]
+			assignment.Enabled[i] = 1
+			continue
+		}
+		assignment.PublicKeys[i] = eddsa.PublicKey{A: tedwards.Point{X: 0, Y: 0}}
+		assignment.Signatures[i] = eddsa.Signature{R: tedwards.Point{X: 0, Y: 0}, S: 0}
+		for j := range assignment.Messages[i] {
+			assignment.Messages[i][j] = 0
+		}
+		assignment.Enabled[i] = 0
+	}
+	assignment.Count = len(pubKeys)
+	return assignment, nil
+}
+
+// paddingSeed derives the key of the padding signature. Anyone can sign
+// with it, which is harmless since disabled slots are never counted.
+const paddingSeed = "eddsa-gnark batch padding"
+
+// paddingSignature returns the public key, signature and message limbs of
+// the fixed signature verified in place of disabled slots.
+func paddingSignature() (eddsa.PublicKey, eddsa.Signature, []frontend.Variable, error) {
+	var pk eddsa.PublicKey
+	var sig eddsa.Signature
+	priv, err := NewKeyFromSeed(twistededwards.BN254, []byte(paddingSeed))
+	if err != nil {
+		return pk, sig, nil, err
+	}
+	sigBytes, err := SignMessage(priv, nil, DefaultMessageLimbs)
+	if err != nil {
+		return pk, sig, nil, err
+	}
+	if err := assignEncodings(twistededwards.BN254, &pk, &sig, priv.Public().Bytes(), sigBytes); err != nil {
+		return pk, sig, nil, err
+	}
+	limbs, err := MessageLimbs(nil, DefaultMessageLimbs)
+	if err != nil {
+		return pk, sig, nil, err
+	}
+	return pk, sig, limbs, nil
+}
+
+// Define implements the circuit for padded batch EdDSA signature
+// verification
+func (circuit *PaddedBatchEdDSACircuit) Define(api frontend.API) error {
+	n := len(circuit.PublicKeys)
+	if len(circuit.Signatures) != n || len(circuit.Messages) != n || len(circuit.Enabled) != n {
+		return fmt.Errorf("batch has %d public keys, %d signatures, %d messages and %d flags", n, len(circuit.Signatures), len(circuit.Messages), len(circuit.Enabled))
+	}
+
+	curve, err := tedwards.NewEdCurve(api, twistededwards.BN254)
+	if err != nil {
+		return err
+	}
+	hash, err := newMiMCFieldHasher(api)
+	if err != nil {
+		return err
+	}
+	padKey, padSig, padMsg, err := paddingSignature()
+	if err != nil {
+		return fmt.Errorf("padding signature: %w", err)
+	}
+
+	var count frontend.Variable = 0
+	for i := range circuit.PublicKeys {
+		on := circuit.Enabled[i]
+		api.AssertIsBoolean(on)
+		count = api.Add(count, on)
+
+		// A disabled slot verifies the padding signature instead, which
+		// always holds, so its own contents never reach the check
+		sel := func(v, pad frontend.Variable) frontend.Variable { return api.Select(on, v, pad) }
+		pk := eddsa.PublicKey{A: tedwards.Point{
+			X: sel(circuit.PublicKeys[i].A.X, padKey.A.X),
+			Y: sel(circuit.PublicKeys[i].A.Y, padKey.A.Y),
+		}}
+		sig := eddsa.Signature{
+			R: tedwards.Point{
+				X: sel(circuit.Signatures[i].R.X, padSig.R.X),
+				Y: sel(circuit.Signatures[i].R.Y, padSig.R.Y),
+			},
+			S: sel(circuit.Signatures[i].S, padSig.S),
+		}
+		if len(circuit.Messages[i]) != len(padMsg) {
+			return fmt.Errorf("message %d has %d limbs, want %d", i, len(circuit.Messages[i]), len(padMsg))
+		}
+		msg := make([]frontend.Variable, len(padMsg))
+		for j := range msg {
+			msg[j] = sel(circuit.Messages[i][j], padMsg[j])
+		}
+
+		if err := verifyMessageSignature(curve, hash, pk, sig, msg); err != nil {
+			return fmt.Errorf("signature %d: %w", i, err)
+		}
+	}
+	api.AssertIsEqual(count, circuit.Count)
+	return nil
+}
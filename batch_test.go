@@ -0,0 +1,204 @@
+package main
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	cryptoeddsa "github.com/consensys/gnark-crypto/signature/eddsa"
+	"github.com/consensys/gnark/test"
+)
+
+// signBatch signs n distinct messages with n fresh keys.
+func signBatch(tb testing.TB, n int) (pubKeys, sigs, msgs [][]byte) {
+	tb.Helper()
+	for i := 0; i < n; i++ {
+		privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+		if err != nil {
+			tb.Fatal("Error creating private key:", err)
+		}
+		msg := []byte{0xde, 0xad, 0xf0, byte(i)}
+		sig, err := SignMessage(privateKey, msg, DefaultMessageLimbs)
+		if err != nil {
+			tb.Fatal("Error signing message:", err)
+		}
+		pubKeys = append(pubKeys, privateKey.Public().Bytes())
+		sigs = append(sigs, sig)
+		msgs = append(msgs, msg)
+	}
+	return pubKeys, sigs, msgs
+}
+
+func TestBatchEdDSACircuit(t *testing.T) {
+	const n = 8
+	pubKeys, sigs, msgs := signBatch(t, n)
+
+	assignment, err := NewBatchAssignment(pubKeys, sigs, msgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert := test.NewAssert(t)
+	assert.SolvingSucceeded(NewBatchCircuit(n), assignment, test.WithCurves(ecc.BN254))
+}
+
+func TestBatchEdDSACircuitTampered(t *testing.T) {
+	const n = 8
+	pubKeys, sigs, msgs := signBatch(t, n)
+
+	// Tamper with a single signature in the middle of the batch
+	sigs[5][0] ^= 0x01
+
+	assignment, err := NewBatchAssignment(pubKeys, sigs, msgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert := test.NewAssert(t)
+	assert.SolvingFailed(NewBatchCircuit(n), assignment, test.WithCurves(ecc.BN254))
+}
+
+func TestMixedHashBatchEdDSACircuit(t *testing.T) {
+	hashes := []HashID{HashMiMC, HashPoseidon2, HashMiMC, HashPoseidon2}
+	var pubKeys, sigs, msgs [][]byte
+	for i, id := range hashes {
+		privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+		if err != nil {
+			t.Fatal("Error creating private key:", err)
+		}
+		msg := []byte{0xde, 0xad, 0xf0, byte(i)}
+		sig, err := SignMessageWith(id, privateKey, msg, DefaultMessageLimbs)
+		if err != nil {
+			t.Fatal("Error signing message:", err)
+		}
+		pubKeys = append(pubKeys, privateKey.Public().Bytes())
+		sigs = append(sigs, sig)
+		msgs = append(msgs, msg)
+	}
+
+	assignment, err := NewMixedHashBatchAssignment(hashes, pubKeys, sigs, msgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert := test.NewAssert(t)
+	circuit := NewMixedHashBatchCircuit(len(hashes))
+	assert.SolvingSucceeded(circuit, assignment, test.WithCurves(ecc.BN254))
+
+	// A signature checked under the other hash does not verify
+	swapped, err := NewMixedHashBatchAssignment([]HashID{HashMiMC, HashMiMC, HashMiMC, HashPoseidon2}, pubKeys, sigs, msgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.SolvingFailed(circuit, swapped, test.WithCurves(ecc.BN254))
+
+	// Nor does one under a selector matching no hash
+	unknown, err := NewMixedHashBatchAssignment(hashes, pubKeys, sigs, msgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unknown.Hashes[0] = len(SupportedHashes)
+	assert.SolvingFailed(circuit, unknown, test.WithCurves(ecc.BN254))
+}
+
+// signWithOneKey signs n distinct messages with a single fresh key.
+func signWithOneKey(tb testing.TB, n int) (pubKey []byte, sigs, msgs [][]byte) {
+	tb.Helper()
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		tb.Fatal("Error creating private key:", err)
+	}
+	for i := 0; i < n; i++ {
+		msg := []byte{0xde, 0xad, 0xf0, byte(i)}
+		sig, err := SignMessage(privateKey, msg, DefaultMessageLimbs)
+		if err != nil {
+			tb.Fatal("Error signing message:", err)
+		}
+		sigs = append(sigs, sig)
+		msgs = append(msgs, msg)
+	}
+	return privateKey.Public().Bytes(), sigs, msgs
+}
+
+func TestSignerBatchEdDSACircuit(t *testing.T) {
+	const n = 4
+	pubKey, sigs, msgs := signWithOneKey(t, n)
+
+	assignment, err := NewSignerBatchAssignment(pubKey, sigs, msgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert := test.NewAssert(t)
+	assert.SolvingSucceeded(NewSignerBatchCircuit(n), assignment, test.WithCurves(ecc.BN254))
+}
+
+func TestSignerBatchEdDSACircuitSwapped(t *testing.T) {
+	const n = 4
+	pubKey, sigs, msgs := signWithOneKey(t, n)
+
+	// Each signature is valid on its own, but not on the other's message
+	sigs[1], sigs[2] = sigs[2], sigs[1]
+
+	assignment, err := NewSignerBatchAssignment(pubKey, sigs, msgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert := test.NewAssert(t)
+	assert.SolvingFailed(NewSignerBatchCircuit(n), assignment, test.WithCurves(ecc.BN254))
+}
+
+func TestPaddedBatchEdDSACircuit(t *testing.T) {
+	const n, nbSigned = 8, 3
+	pubKeys, sigs, msgs := signBatch(t, nbSigned+1)
+
+	assignment, err := NewPaddedBatchAssignment(n, pubKeys[:nbSigned], sigs[:nbSigned], msgs[:nbSigned])
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert := test.NewAssert(t)
+	assert.SolvingSucceeded(NewPaddedBatchCircuit(n), assignment, test.WithCurves(ecc.BN254))
+
+	// A disabled slot holding garbage, here a tampered signature, is ignored
+	garbage, err := NewBatchAssignment([][]byte{pubKeys[nbSigned]}, [][]byte{sigs[nbSigned]}, [][]byte{msgs[nbSigned]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	garbage.Signatures[0].S = 12345
+	garbage.Messages[0][0] = 42
+	assignment.PublicKeys[n-1] = garbage.PublicKeys[0]
+	assignment.Signatures[n-1] = garbage.Signatures[0]
+	assignment.Messages[n-1] = garbage.Messages[0]
+	assert.SolvingSucceeded(NewPaddedBatchCircuit(n), assignment, test.WithCurves(ecc.BN254))
+
+	// Enabling it makes the batch fail
+	assignment.Enabled[n-1] = 1
+	assignment.Count = nbSigned + 1
+	assert.SolvingFailed(NewPaddedBatchCircuit(n), assignment, test.WithCurves(ecc.BN254))
+	assignment.Enabled[n-1] = 0
+	assignment.Count = nbSigned
+
+	// So does a count that does not match the enabled slots, or a flag that
+	// is not a bit
+	assignment.Count = nbSigned + 1
+	assert.SolvingFailed(NewPaddedBatchCircuit(n), assignment, test.WithCurves(ecc.BN254))
+	assignment.Count = nbSigned
+	assignment.Enabled[0] = 2
+	assert.SolvingFailed(NewPaddedBatchCircuit(n), assignment, test.WithCurves(ecc.BN254))
+}
+
+func TestPaddedBatchEdDSACircuitTampered(t *testing.T) {
+	const n, nbSigned = 8, 3
+	pubKeys, sigs, msgs := signBatch(t, nbSigned)
+
+	// A tampered signature in an enabled slot still fails the batch
+	sigs[1][0] ^= 0x01
+
+	assignment, err := NewPaddedBatchAssignment(n, pubKeys, sigs, msgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert := test.NewAssert(t)
+	assert.SolvingFailed(NewPaddedBatchCircuit(n), assignment, test.WithCurves(ecc.BN254))
+}
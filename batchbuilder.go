@@ -0,0 +1,60 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+)
+
+// BatchAssignmentBuilder fills the slots of a BatchEdDSACircuit assignment
+// one signature at a time, for callers reading signatures from a stream
+// rather than holding them all. Each signature is encoded into its slot as
+// it is added, so the raw inputs are not kept.
+type BatchAssignmentBuilder struct {
+	assignment *BatchEdDSACircuit
+	n          int
+}
+
+// NewBatchAssignmentBuilder returns a builder for the assignment of a batch
+// circuit with capacity slots, as returned by NewBatchCircuit(capacity).
+func NewBatchAssignmentBuilder(capacity int) *BatchAssignmentBuilder {
+	return &BatchAssignmentBuilder{assignment: NewBatchCircuit(capacity)}
+}
+
+// AddSignature assigns the next slot to sig, the signature of msg under
+// pubKey. It fails if every slot is assigned or the inputs cannot be
+// assigned, in which case the slot stays free.
+func (b *BatchAssignmentBuilder) AddSignature(pubKey, sig, msg []byte) error {
+	if b.assignment == nil {
+		return errors.New("batch assignment already built")
+	}
+	if b.n == len(b.assignment.PublicKeys) {
+		return fmt.Errorf("batch is full: the circuit has %d slots", b.n)
+	}
+	limbs, err := MessageLimbs(msg, DefaultMessageLimbs)
+	if err != nil {
+		return fmt.Errorf("message %d: %w", b.n, err)
+	}
+	if err := assignEncodings(twistededwards.BN254, &b.assignment.PublicKeys[b.n], &b.assignment.Signatures[b.n], pubKey, sig); err != nil {
+		return fmt.Errorf("signature %d: %w", b.n, err)
+	}
+	b.assignment.Messages[b.n] = limbs
+	b.n++
+	return nil
+}
+
+// Build returns the assignment once every slot is assigned: a batch circuit
+// has no unused slots, see PaddedBatchEdDSACircuit for that. The builder
+// cannot be used afterwards.
+func (b *BatchAssignmentBuilder) Build() (*BatchEdDSACircuit, error) {
+	if b.assignment == nil {
+		return nil, errors.New("batch assignment already built")
+	}
+	if b.n != len(b.assignment.PublicKeys) {
+		return nil, fmt.Errorf("batch has %d signatures, the circuit has %d slots", b.n, len(b.assignment.PublicKeys))
+	}
+	assignment := b.assignment
+	b.assignment = nil
+	return assignment, nil
+}
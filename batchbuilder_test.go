@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/test"
+)
+
+func TestBatchAssignmentBuilder(t *testing.T) {
+	const n = 32
+	pubKeys, sigs, msgs := signBatch(t, n+1)
+
+	b := NewBatchAssignmentBuilder(n)
+	for i := 0; i < n; i++ {
+		if _, err := b.Build(); err == nil {
+			t.Fatalf("Expected building with %d of %d signatures to fail", i, n)
+		}
+		if err := b.AddSignature(pubKeys[i], sigs[i], msgs[i]); err != nil {
+			t.Fatalf("Error adding signature %d: %v", i, err)
+		}
+	}
+	if err := b.AddSignature(pubKeys[n], sigs[n], msgs[n]); err == nil {
+		t.Fatal("Expected adding a signature past the capacity to fail")
+	}
+	assignment, err := b.Build()
+	if err != nil {
+		t.Fatal("Error building assignment:", err)
+	}
+	if err := b.AddSignature(pubKeys[0], sigs[0], msgs[0]); err == nil {
+		t.Fatal("Expected adding a signature after Build to fail")
+	}
+
+	assert := test.NewAssert(t)
+	assert.SolvingSucceeded(NewBatchCircuit(n), assignment, test.WithCurves(ecc.BN254))
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+
+	eddsabn254 "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards/eddsa"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	tedwards "github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/signature/eddsa"
+)
+
+// BridgeEdDSACircuit checks a signature made with SignMessage on BN254 and
+// exposes the outcome as the public bit Valid instead of failing on invalid
+// signatures, together with the MiMC digest of the secret Message limbs as
+// the public Digest. It is meant as a building block of a larger composed
+// circuit, or of a statement checked alongside other proofs, that decides
+// what to do with the result and binds Digest to data it uses elsewhere.
+//
+// The public inputs are, in this order, which is the order of the public
+// witness and of the inputs of an exported verifier, after the constant one
+// that is not part of the witness:
+//
+//  0. Valid: 1 if the signature verifies, 0 otherwise
+//  1. Digest: HashMessage of the message
+//  2. PublicKey.A.X
+//  3. PublicKey.A.Y
+//  4. Signature.R.X
+//  5. Signature.R.Y
+//  6. Signature.S
+//
+// Only the verification equation is reflected in Valid. The digest must
+// match the message, and the public key and R must be on the curve, for any
+// assignment to satisfy the circuit.
+type BridgeEdDSACircuit struct {
+	Valid     frontend.Variable `gnark:",public"`
+	Digest    frontend.Variable `gnark:",public"`
+	PublicKey eddsa.PublicKey   `gnark:",public"`
+	Signature eddsa.Signature   `gnark:",public"`
+
+	Message []frontend.Variable
+}
+
+// NewBridgeCircuit returns a bridge circuit whose Message holds nbLimbs
+// limbs.
+func NewBridgeCircuit(nbLimbs int) *BridgeEdDSACircuit {
+	return &BridgeEdDSACircuit{Message: make([]frontend.Variable, nbLimbs)}
+}
+
+// NewBridgeAssignment returns an assignment of the bridge circuit with
+// nbLimbs message limbs for a BN254 public key, a signature and the original
+// message. Digest is set to HashMessage of msg, and Valid to whether sig
+// verifies natively, so a well-formed signature that does not verify is
+// assigned with Valid 0 rather than rejected.
+func NewBridgeAssignment(pubKey, sig, msg []byte, nbLimbs int) (*BridgeEdDSACircuit, error) {
+	limbs, err := MessageLimbs(msg, nbLimbs)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := HashMessage(msg, nbLimbs)
+	if err != nil {
+		return nil, err
+	}
+	assignment := &BridgeEdDSACircuit{Message: limbs, Digest: new(big.Int).SetBytes(digest)}
+	if err := assignEncodings(twistededwards.BN254, &assignment.PublicKey, &assignment.Signature, pubKey, sig); err != nil {
+		return nil, err
+	}
+
+	var pub eddsabn254.PublicKey
+	if _, err := pub.SetBytes(pubKey); err != nil {
+		return nil, fmt.Errorf("decoding public key: %w", err)
+	}
+	hFunc, err := nativeHash(twistededwards.BN254, HashMiMC)
+	if err != nil {
+		return nil, err
+	}
+	valid, err := pub.Verify(sig, digest, hFunc)
+	assignment.Valid = 0
+	if valid && err == nil {
+		assignment.Valid = 1
+	}
+	return assignment, nil
+}
+
+// Define implements the circuit for EdDSA verification with a public result
+func (circuit *BridgeEdDSACircuit) Define(api frontend.API) error {
+	curve, err := tedwards.NewEdCurve(api, twistededwards.BN254)
+	if err != nil {
+		return err
+	}
+	hash, err := newMiMCFieldHasher(api)
+	if err != nil {
+		return err
+	}
+
+	api.AssertIsEqual(absorbChunks(hash, circuit.Message), circuit.Digest)
+
+	// The equation checked by eddsa.Verify, [cofactor]([S]G - [H(R,A,M)]A - R)
+	// is the neutral element, evaluated to a bit instead of asserted
+	sig, pubKey := circuit.Signature, circuit.PublicKey
+	curve.AssertIsOnCurve(pubKey.A)
+	curve.AssertIsOnCurve(sig.R)
+	hash.Write(sig.R.X, sig.R.Y, pubKey.A.X, pubKey.A.Y, circuit.Digest)
+	hRAM := hash.Sum()
+	params := curve.Params()
+	base := tedwards.Point{X: params.Base[0], Y: params.Base[1]}
+	q := curve.DoubleBaseScalarMul(base, curve.Neg(pubKey.A), sig.S, hRAM)
+	q = curve.Add(curve.Neg(q), sig.R)
+	for c := params.Cofactor.Uint64(); c > 1; c >>= 1 {
+		q = curve.Double(q)
+	}
+	valid := api.And(api.IsZero(q.X), api.IsZero(api.Sub(q.Y, 1)))
+	api.AssertIsEqual(circuit.Valid, valid)
+	return nil
+}
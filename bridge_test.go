@@ -0,0 +1,61 @@
+package main
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+func TestBridgeEdDSACircuit(t *testing.T) {
+	pubKeys, sigs, msgs := signBatch(t, 2)
+	assignment, err := NewBridgeAssignment(pubKeys[0], sigs[0], msgs[0], DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+	assert := test.NewAssert(t)
+	assert.SolvingSucceeded(NewBridgeCircuit(DefaultMessageLimbs), assignment, test.WithCurves(ecc.BN254))
+
+	// The public witness starts with Valid and the native MiMC digest of the
+	// message
+	publicWitness, err := frontend.NewWitness(assignment, ecc.BN254.ScalarField(), frontend.PublicOnly())
+	if err != nil {
+		t.Fatal(err)
+	}
+	public := publicWitness.Vector().(fr.Vector)
+	if len(public) != 7 {
+		t.Fatalf("Expected 7 public inputs, got %d", len(public))
+	}
+	digest, err := HashMessage(msgs[0], DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !public[0].IsOne() {
+		t.Fatal("Expected Valid to be the first public input, got", public[0].String())
+	}
+	if public[1].BigInt(new(big.Int)).Cmp(new(big.Int).SetBytes(digest)) != 0 {
+		t.Fatalf("Expected Digest %x as the second public input, got %s", digest, public[1].String())
+	}
+
+	// Claiming the signature is invalid fails, as does another digest
+	assignment.Valid = 0
+	assert.SolvingFailed(NewBridgeCircuit(DefaultMessageLimbs), assignment, test.WithCurves(ecc.BN254))
+	assignment.Valid = 1
+	assignment.Digest = 1
+	assert.SolvingFailed(NewBridgeCircuit(DefaultMessageLimbs), assignment, test.WithCurves(ecc.BN254))
+
+	// A signature by another key is proven invalid, not rejected
+	invalid, err := NewBridgeAssignment(pubKeys[0], sigs[1], msgs[0], DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+	if invalid.Valid != 0 {
+		t.Fatal("Expected Valid 0 for a signature by another key")
+	}
+	assert.SolvingSucceeded(NewBridgeCircuit(DefaultMessageLimbs), invalid, test.WithCurves(ecc.BN254))
+	invalid.Valid = 1
+	assert.SolvingFailed(NewBridgeCircuit(DefaultMessageLimbs), invalid, test.WithCurves(ecc.BN254))
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/constraint"
+)
+
+// Names of the entries of a bundle written by ProverBundle.
+const (
+	bundleVerifyingKey = "groth16.vk"
+	bundleProof        = "proof.bin"
+	bundleInputs       = "inputs.json"
+)
+
+// ProverBundle writes to path a tar archive holding everything another
+// machine needs to check proof: vk and proof, with the headers SaveKeys and
+// SaveProof write, and the public inputs pubKey, sig and msg as an
+// AssignmentJSON object. The archive is checked by VerifyBundle, which needs
+// neither ccs nor the proving key.
+func ProverBundle(ccs constraint.ConstraintSystem, vk groth16.VerifyingKey, proof groth16.Proof, pubKey, sig, msg []byte, path string) error {
+	h, err := newFileHeader(ccs)
+	if err != nil {
+		return err
+	}
+	var inputs bytes.Buffer
+	if err := WriteAssignmentJSON(&inputs, pubKey, sig, msg); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, entry := range []struct {
+		name string
+		src  io.WriterTo
+	}{
+		{bundleVerifyingKey, headed{h.encode(), vk}},
+		{bundleProof, headed{h.encode(), proof}},
+		{bundleInputs, &inputs},
+	} {
+		var content bytes.Buffer
+		if _, err := entry.src.WriteTo(&content); err != nil {
+			return fmt.Errorf("serializing %s: %w", entry.name, err)
+		}
+		hdr := &tar.Header{Name: entry.name, Mode: 0o644, Size: int64(content.Len())}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return fmt.Errorf("writing bundle: %w", err)
+		}
+		if _, err := tw.Write(content.Bytes()); err != nil {
+			return fmt.Errorf("writing bundle: %w", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("writing bundle: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("writing bundle: %w", err)
+	}
+	return nil
+}
+
+// VerifyBundle verifies the proof in the archive at path, written by
+// ProverBundle, against the verifying key and public inputs it holds. The
+// curve is read from the header of the verifying key, and a proof produced
+// for another circuit than the verifying key is rejected as such. The
+// verifying key is taken from the bundle, so a verifier that does not trust
+// the prover must check it against one obtained from the setup separately.
+func VerifyBundle(path string) error {
+	entries, err := readBundle(path)
+	if err != nil {
+		return err
+	}
+
+	vkEntry := bytes.NewReader(entries[bundleVerifyingKey])
+	vkHeader, err := readHeader(bundleVerifyingKey, vkEntry)
+	if err != nil {
+		return fmt.Errorf("loading verifying key: %w", err)
+	}
+	inner, err := InnerCurve(vkHeader.curve)
+	if err != nil {
+		return err
+	}
+	vk := groth16.NewVerifyingKey(vkHeader.curve)
+	if err := readFrom(bundleVerifyingKey, vkEntry, vk); err != nil {
+		return fmt.Errorf("loading verifying key: %w", err)
+	}
+
+	proofEntry := bytes.NewReader(entries[bundleProof])
+	proofHeader, err := readHeader(bundleProof, proofEntry)
+	if err != nil {
+		return fmt.Errorf("loading proof: %w", err)
+	}
+	if proofHeader != vkHeader {
+		return fmt.Errorf("loading proof: %s was produced for %s circuit version %s, not %s circuit version %s",
+			bundleProof, proofHeader.curve, proofHeader.circuit, vkHeader.curve, vkHeader.circuit)
+	}
+	proof := groth16.NewProof(vkHeader.curve)
+	if err := readFrom(bundleProof, proofEntry, proof); err != nil {
+		return fmt.Errorf("loading proof: %w", err)
+	}
+
+	var in AssignmentJSON
+	if err := json.Unmarshal(entries[bundleInputs], &in); err != nil {
+		return fmt.Errorf("decoding %s: %w", bundleInputs, err)
+	}
+	msg, err := decodeHexField("message", in.Message)
+	if err != nil {
+		return err
+	}
+	pubKey, err := decodeHexField("publicKey", in.PublicKey)
+	if err != nil {
+		return err
+	}
+	sig, err := decodeHexField("signature", in.Signature)
+	if err != nil {
+		return err
+	}
+	if err := ValidatePublicKey(inner, pubKey); err != nil {
+		return fmt.Errorf("invalid public key: %w", err)
+	}
+	return VerifyProof(vk, proof, pubKey, sig, msg)
+}
+
+// readBundle returns the entries of the bundle at path by name, after
+// checking that it holds every entry ProverBundle writes.
+func readBundle(path string) (map[string][]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	entries := make(map[string][]byte)
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading bundle %s: %w", path, err)
+		}
+		switch hdr.Name {
+		case bundleVerifyingKey, bundleProof, bundleInputs:
+			if entries[hdr.Name], err = io.ReadAll(tr); err != nil {
+				return nil, fmt.Errorf("reading %s from bundle %s: %w", hdr.Name, path, err)
+			}
+		}
+	}
+	for _, name := range []string{bundleVerifyingKey, bundleProof, bundleInputs} {
+		if _, ok := entries[name]; !ok {
+			return nil, fmt.Errorf("bundle %s has no %s", path, name)
+		}
+	}
+	return entries, nil
+}
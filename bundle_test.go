@@ -0,0 +1,62 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+)
+
+func TestProverBundle(t *testing.T) {
+	pubKeys, sigs, msgs := signBatch(t, 2)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "bundle.tar")
+
+	// Machine A compiles, runs the setup, proves and bundles the result
+	ccs, err := CompileCircuit(ecc.BN254, backend.GROTH16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, vk, err := SetupGroth16(ccs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assignment, err := NewAssignment(pubKeys[0], sigs[0], msgs[0], DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := ProveWithGroth16(ccs, pk, assignment)
+	if err != nil {
+		t.Fatal("Error proving:", err)
+	}
+	if err := ProverBundle(ccs, vk, proof, pubKeys[0], sigs[0], msgs[0], path); err != nil {
+		t.Fatal("Error writing bundle:", err)
+	}
+
+	// Machine B only has the bundle
+	if err := VerifyBundle(path); err != nil {
+		t.Fatal("Error verifying bundle:", err)
+	}
+
+	// The proof does not hold for other public inputs
+	tampered := filepath.Join(dir, "tampered.tar")
+	if err := ProverBundle(ccs, vk, proof, pubKeys[1], sigs[1], msgs[1], tampered); err != nil {
+		t.Fatal("Error writing bundle:", err)
+	}
+	if err := VerifyBundle(tampered); err == nil {
+		t.Fatal("Expected an error verifying a proof against other public inputs")
+	}
+
+	if err := VerifyBundle(filepath.Join(dir, "missing.tar")); err == nil {
+		t.Fatal("Expected an error for a missing bundle")
+	}
+	notBundle := filepath.Join(dir, "keys")
+	if err := SaveKeys(ccs, pk, vk, notBundle); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyBundle(filepath.Join(notBundle, "groth16.vk")); err == nil || !strings.Contains(err.Error(), "bundle") {
+		t.Fatal("Expected an error for a file that is not a bundle, got:", err)
+	}
+}
@@ -1,34 +1,269 @@
 package main
 
-// import (
-// 	"github.com/consensys/gnark-crypto/ecc/twistededwards"
-// 	"github.com/consensys/gnark/frontend"
-// 	tedwards "github.com/consensys/gnark/std/algebra/native/twistededwards"
-// 	"github.com/consensys/gnark/std/hash/mimc"
-// 	"github.com/consensys/gnark/std/signature/eddsa"
-// )
-
-// // EdDSACircuit defines the circuit for EdDSA signature verification
-// type EdDSACircuit struct {
-// 	PublicKey eddsa.PublicKey   `gnark:",public"`
-// 	Signature eddsa.Signature   `gnark:",public"`
-// 	Message   frontend.Variable `gnark:",public"`
-// }
-
-// // Define implements the circuit for EdDSA signature verification
-// func (circuit *EdDSACircuit) Define(api frontend.API) error {
-// 	// Initialize the twisted Edwards curve for BN254
-// 	curve, err := tedwards.NewEdCurve(api, twistededwards.BN254)
-// 	if err != nil {
-// 		return err
-// 	}
-
-// 	// Initialize the MiMC hash function
-// 	hash, err := mimc.NewMiMC(api)
-// 	if err != nil {
-// 		return err
-// 	}
-
-// 	// Verify the signature in the constraint system
-// 	return eddsa.Verify(curve, circuit.Signature, circuit.Message, circuit.PublicKey, &hash)
-// }
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	frbls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	frbls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	frbls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	frbn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	tedwards "github.com/consensys/gnark/std/algebra/native/twistededwards"
+	stdhash "github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/signature/eddsa"
+)
+
+// EdDSACircuit defines the circuit for EdDSA signature verification.
+// The signature is over the digest of the Message limbs, whose count is
+// fixed at compile time, under the hash selected by Hash (MiMC by default).
+// A non-empty MiMCKey, a big-endian field element, keys MiMC with a
+// deployment-specific initial state; signatures must then be made with
+// SignMessageKeyed under the same key. A non-nil NewHash overrides Hash with
+// any other in-circuit hash; signatures must then be made with
+// SignMessageUsing and its native counterpart. gnark's test engine cannot
+// clone a circuit whose NewHash is set, so such circuits are checked by
+// proving rather than with test.Assert. Curve selects the twisted Edwards
+// curve keys live on when the compiled field has several, such as
+// twistededwards.BLS12_381_BANDERSNATCH; the default is InnerCurve.
+// RejectNeutralKey constrains the public key not to be the neutral element
+// (0, 1), under which any R = [S]G verifies; it rejects (0, -1), the only
+// other point with X = 0, along with it. UseCommitment verifies the
+// signature with a lookupCurve, whose table lookups are checked against a
+// commitment to the witness: the circuit is smaller, but needs a setup of
+// its own.
+type EdDSACircuit struct {
+	PublicKey eddsa.PublicKey     `gnark:",public"`
+	Signature eddsa.Signature     `gnark:",public"`
+	Message   []frontend.Variable `gnark:",public"`
+
+	Curve            twistededwards.ID `gnark:"-"`
+	RejectNeutralKey bool              `gnark:"-"`
+	UseCommitment    bool              `gnark:"-"`
+
+	Hash    HashID                                              `gnark:"-"`
+	MiMCKey []byte                                              `gnark:"-"`
+	NewHash func(api frontend.API) (stdhash.FieldHasher, error) `gnark:"-"`
+}
+
+// NewEdDSACircuit returns a circuit whose Message holds nbLimbs limbs, for
+// use both as the compilation template and as an assignment.
+func NewEdDSACircuit(nbLimbs int) *EdDSACircuit {
+	return &EdDSACircuit{Message: make([]frontend.Variable, nbLimbs)}
+}
+
+// NewAssignment returns an assignment of the circuit with nbLimbs message
+// limbs for a BN254 public key, a signature produced by SignMessage and the
+// original message.
+func NewAssignment(pubKey, sig, msg []byte, nbLimbs int) (*EdDSACircuit, error) {
+	return NewAssignmentOn(twistededwards.BN254, pubKey, sig, msg, nbLimbs)
+}
+
+// NewAssignmentOn is NewAssignment for a public key and signature on the
+// twisted Edwards curve inner. The assignment records inner in its Curve, so
+// that proving or solving it for a circuit over another field fails with an
+// error naming both curves.
+func NewAssignmentOn(inner twistededwards.ID, pubKey, sig, msg []byte, nbLimbs int) (*EdDSACircuit, error) {
+	assignment := NewEdDSACircuit(nbLimbs)
+	assignment.Curve = inner
+	if err := AssignMessage(assignment, msg); err != nil {
+		return nil, err
+	}
+	if err := assignEncodings(inner, &assignment.PublicKey, &assignment.Signature, pubKey, sig); err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
+
+// assignEncodings assigns the compressed public key pubKey and signature sig
+// on inner to pk and s. The gnark Assign helpers panic on points they
+// cannot decode; assignEncodings reports those as errors instead.
+func assignEncodings(inner twistededwards.ID, pk *eddsa.PublicKey, s *eddsa.Signature, pubKey, sig []byte) (err error) {
+	if err := checkEncodingSizes(inner, pubKey, sig); err != nil {
+		return err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("decoding public key and signature: %v", r)
+		}
+	}()
+	pk.Assign(inner, pubKey)
+	*s, err = SignatureToCircuit(inner, sig)
+	return err
+}
+
+// SignatureToCircuit returns the circuit value of sig, a signature on the
+// twisted Edwards curve inner as produced by SignMessageOn. It is what
+// eddsa.Signature.Assign sets, returned as a value, with malformed
+// encodings reported as errors instead of panics.
+func SignatureToCircuit(inner twistededwards.ID, sig []byte) (s eddsa.Signature, err error) {
+	outer, err := outerCurve(inner)
+	if err != nil {
+		return eddsa.Signature{}, err
+	}
+	if size := 2 * fieldBytes(outer); len(sig) != size {
+		return eddsa.Signature{}, fmt.Errorf("signature must be %d bytes, got %d", size, len(sig))
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			s, err = eddsa.Signature{}, fmt.Errorf("decoding signature: %v", r)
+		}
+	}()
+	s.Assign(inner, sig)
+	return s, nil
+}
+
+// checkAssignmentCurve rejects an EdDSACircuit assignment whose Curve is not
+// defined over field. Its coordinates would otherwise be reduced into field
+// and fail as an unsatisfied constraint. Other assignments, and those that
+// leave Curve unset, are not checked.
+func checkAssignmentCurve(field *big.Int, assignment frontend.Circuit) error {
+	a, ok := assignment.(*EdDSACircuit)
+	if !ok || a.Curve == twistededwards.UNKNOWN {
+		return nil
+	}
+	for _, p := range slices.Concat(supportedCurves, []curvePair{bandersnatch}) {
+		if p.inner == a.Curve && p.outer.ScalarField().Cmp(field) == 0 {
+			return nil
+		}
+	}
+	outer, err := fieldCurve(field)
+	if err != nil {
+		return err
+	}
+	if _, err := InnerCurve(outer); err != nil {
+		return err
+	}
+	return fmt.Errorf("public key curve %s does not match circuit curve %s", innerCurveName(a.Curve), curveName(outer))
+}
+
+// PublicInputs returns the public inputs of assignment as elements of the
+// scalar field of curve, in the order of the circuit's public variables,
+// which is the order an on-chain verifier expects them in:
+//
+//	PublicKey.A.X, PublicKey.A.Y, Signature.R.X, Signature.R.Y, Signature.S,
+//	Message[0], ..., Message[len(Message)-1]
+//
+// gnark's implicit constant wire is not included.
+func PublicInputs(curve ecc.ID, assignment *EdDSACircuit) ([]*big.Int, error) {
+	if err := checkAssignmentCurve(curve.ScalarField(), assignment); err != nil {
+		return nil, err
+	}
+	publicWitness, err := frontend.NewWitness(assignment, curve.ScalarField(), frontend.PublicOnly())
+	if err != nil {
+		return nil, fmt.Errorf("creating public witness: %w", err)
+	}
+	switch v := publicWitness.Vector().(type) {
+	case frbn254.Vector:
+		return toBigInts(v), nil
+	case frbls12381.Vector:
+		return toBigInts(v), nil
+	case frbls12377.Vector:
+		return toBigInts(v), nil
+	case frbls24315.Vector:
+		return toBigInts(v), nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %s", curve)
+	}
+}
+
+// toBigInts converts a vector of gnark-crypto field elements.
+func toBigInts[T any, P interface {
+	*T
+	BigInt(res *big.Int) *big.Int
+}](v []T) []*big.Int {
+	values := make([]*big.Int, len(v))
+	for i := range v {
+		values[i] = P(&v[i]).BigInt(new(big.Int))
+	}
+	return values
+}
+
+// checkEncodingSizes rejects public keys and signatures whose length would
+// make the gnark Assign helpers panic. A compressed point takes as many
+// bytes as a scalar field element of the outer curve, and a signature is a
+// point followed by a scalar.
+func checkEncodingSizes(inner twistededwards.ID, pubKey, sig []byte) error {
+	outer, err := outerCurve(inner)
+	if err != nil {
+		return err
+	}
+	size := fieldBytes(outer)
+	if len(pubKey) != size {
+		return fmt.Errorf("public key must be %d bytes, got %d", size, len(pubKey))
+	}
+	if len(sig) != 2*size {
+		return fmt.Errorf("signature must be %d bytes, got %d", 2*size, len(sig))
+	}
+	return nil
+}
+
+// Define implements the circuit for EdDSA signature verification
+func (circuit *EdDSACircuit) Define(api frontend.API) error {
+	// Initialize the twisted Edwards curve embedded in the compiled field
+	pair, err := compiledCurve(api, circuit.Curve)
+	if err != nil {
+		return err
+	}
+	curve, err := tedwards.NewEdCurve(api, pair.inner)
+	if err != nil {
+		return err
+	}
+	curve = commitCurve(curve, circuit.UseCommitment)
+
+	if circuit.RejectNeutralKey {
+		api.AssertIsDifferent(circuit.PublicKey.A.X, 0)
+	}
+
+	// Initialize the hash function
+	hash, err := circuit.newHash(api)
+	if err != nil {
+		return err
+	}
+
+	// Verify the signature in the constraint system
+	return verifyMessageSignature(curve, hash, circuit.PublicKey, circuit.Signature, circuit.Message)
+}
+
+func (circuit *EdDSACircuit) newHash(api frontend.API) (stdhash.FieldHasher, error) {
+	if circuit.NewHash != nil {
+		if len(circuit.MiMCKey) > 0 {
+			return nil, errors.New("a mimc key cannot be used with a custom hash")
+		}
+		return circuit.NewHash(api)
+	}
+	if len(circuit.MiMCKey) > 0 {
+		if circuit.Hash != HashMiMC {
+			return nil, fmt.Errorf("a mimc key cannot be used with %s", circuit.Hash)
+		}
+		return newKeyedMiMCFieldHasher(api, circuit.MiMCKey)
+	}
+	hashFunc, err := NewHashFunc(circuit.Hash)
+	if err != nil {
+		return nil, err
+	}
+	return hashFunc.Circuit(api)
+}
+
+// verifyMessageSignature hashes the message limbs into the digest that was
+// signed and verifies sig over it. hash must be freshly reset and is reset
+// again before it returns, so that it can be reused for the next signature.
+func verifyMessageSignature(curve tedwards.Curve, hash stdhash.FieldHasher, pubKey eddsa.PublicKey, sig eddsa.Signature, message []frontend.Variable) error {
+	digest := absorbChunks(hash, message)
+	err := eddsa.Verify(curve, sig, digest, pubKey, hash)
+	hash.Reset()
+	return err
+}
+
+// absorbChunks returns the digest of chunks under hash, absorbing them as
+// HashChunks does natively, and leaves hash reset.
+func absorbChunks(hash stdhash.FieldHasher, chunks []frontend.Variable) frontend.Variable {
+	hash.Write(chunks...)
+	digest := hash.Sum()
+	hash.Reset()
+	return digest
+}
@@ -2,12 +2,19 @@ package main
 
 import (
 	"crypto/rand"
+	"errors"
+	"math/big"
+	"reflect"
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	edwardsbn254 "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
 	"github.com/consensys/gnark-crypto/ecc/twistededwards"
 	cryptoeddsa "github.com/consensys/gnark-crypto/signature/eddsa"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/signature/eddsa"
 	"github.com/consensys/gnark/test"
 )
 
@@ -25,17 +32,23 @@ func TestEdDSACircuit(t *testing.T) {
 	// Define a message to sign
 	msg := []byte{0xde, 0xad, 0xf0, 0x0d}
 
+	// Hash the message into the digest the circuit recomputes
+	digest, err := HashMessage(msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error hashing message:", err)
+	}
+
 	// Create a MiMC hash function
 	hFunc := mimc.NewMiMC()
-	
-	// Sign the message
-	signature, err := privateKey.Sign(msg, hFunc)
+
+	// Sign the digest
+	signature, err := privateKey.Sign(digest, hFunc)
 	if err != nil {
 		t.Fatal("Error signing message:", err)
 	}
 
 	// Verify the signature (outside the circuit)
-	isValid, err := publicKey.Verify(signature, msg, hFunc)
+	isValid, err := publicKey.Verify(signature, digest, hFunc)
 	if err != nil {
 		t.Fatal("Error verifying signature:", err)
 	}
@@ -44,32 +57,364 @@ func TestEdDSACircuit(t *testing.T) {
 	}
 
 	// Create the circuit
-	var circuit EdDSACircuit
+	circuit := NewEdDSACircuit(DefaultMessageLimbs)
 
 	// Create the witness assignment
-	var validAssignment EdDSACircuit
-
-	// Assign the message value
-	validAssignment.Message = msg
-
-	// Assign the public key
-	validAssignment.PublicKey.Assign(twistededwards.BN254, publicKey.Bytes())
-
-	// Assign the signature
-	validAssignment.Signature.Assign(twistededwards.BN254, signature)
+	validAssignment, err := NewAssignment(publicKey.Bytes(), signature, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
 
 	// Create an invalid assignment with tampered signature
 	tamperedSignature := make([]byte, len(signature))
 	copy(tamperedSignature, signature)
 	tamperedSignature[0] ^= 0x01 // Flip a bit
 
-	var invalidAssignment EdDSACircuit
-	invalidAssignment.Message = msg
-	invalidAssignment.PublicKey.Assign(twistededwards.BN254, publicKey.Bytes())
-	invalidAssignment.Signature.Assign(twistededwards.BN254, tamperedSignature)
+	invalidAssignment, err := NewAssignment(publicKey.Bytes(), tamperedSignature, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+
+	// Create an assignment of the valid signature under another public key
+	otherKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal("Error creating private key:", err)
+	}
+	wrongKeyAssignment, err := NewAssignment(otherKey.Public().Bytes(), signature, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
 
 	// Run the test
 	assert := test.NewAssert(t)
-	assert.SolvingSucceeded(&circuit, &validAssignment, test.WithCurves(curve))
-	assert.SolvingFailed(&circuit, &invalidAssignment, test.WithCurves(curve))
+	assert.SolvingSucceeded(circuit, validAssignment, test.WithCurves(curve))
+	assert.SolvingFailed(circuit, invalidAssignment, test.WithCurves(curve))
+	assert.SolvingFailed(circuit, wrongKeyAssignment, test.WithCurves(curve))
+}
+
+func TestEdDSACircuitLongMessage(t *testing.T) {
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal("Error creating private key:", err)
+	}
+
+	// A 200-byte message spans seven limbs
+	msg := make([]byte, 200)
+	if _, err := rand.Read(msg); err != nil {
+		t.Fatal(err)
+	}
+
+	signature, err := SignMessage(privateKey, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error signing message:", err)
+	}
+	assignment, err := NewAssignment(privateKey.Public().Bytes(), signature, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+
+	// Changing a single byte of the message must break the signature
+	msg[150] ^= 0x01
+	tamperedAssignment, err := NewAssignment(privateKey.Public().Bytes(), signature, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+
+	assert := test.NewAssert(t)
+	circuit := NewEdDSACircuit(DefaultMessageLimbs)
+	assert.SolvingSucceeded(circuit, assignment, test.WithCurves(ecc.BN254))
+	assert.SolvingFailed(circuit, tamperedAssignment, test.WithCurves(ecc.BN254))
+}
+
+func TestEdDSACircuitShortMessages(t *testing.T) {
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal("Error creating private key:", err)
+	}
+	pubKey := privateKey.Public().Bytes()
+
+	assert := test.NewAssert(t)
+	circuit := NewEdDSACircuit(DefaultMessageLimbs)
+	for _, msg := range [][]byte{nil, {}, {0x01}, {0xff}} {
+		signature, err := SignMessage(privateKey, msg, DefaultMessageLimbs)
+		if err != nil {
+			t.Fatalf("Error signing %d-byte message: %v", len(msg), err)
+		}
+
+		// The native digest must match the one the circuit recomputes
+		digest, err := HashMessage(msg, DefaultMessageLimbs)
+		if err != nil {
+			t.Fatal("Error hashing message:", err)
+		}
+		isValid, err := privateKey.Public().Verify(signature, digest, mimc.NewMiMC())
+		if err != nil || !isValid {
+			t.Fatalf("Signature over %d-byte message does not verify natively: %v", len(msg), err)
+		}
+		assignment, err := NewAssignment(pubKey, signature, msg, DefaultMessageLimbs)
+		if err != nil {
+			t.Fatal("Error creating assignment:", err)
+		}
+		assert.SolvingSucceeded(circuit, assignment, test.WithCurves(ecc.BN254))
+
+		// The signature must not carry over to another short message
+		tamperedAssignment, err := NewAssignment(pubKey, signature, []byte{0x02}, DefaultMessageLimbs)
+		if err != nil {
+			t.Fatal("Error creating assignment:", err)
+		}
+		assert.SolvingFailed(circuit, tamperedAssignment, test.WithCurves(ecc.BN254))
+	}
+}
+
+func TestAssignMessageAtModulus(t *testing.T) {
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal("Error creating private key:", err)
+	}
+	pubKey := privateKey.Public().Bytes()
+
+	// Messages whose bytes encode the modulus, or just above it, are split
+	// into limbs instead of being reduced to 0 or 1
+	modulus := ecc.BN254.ScalarField()
+	atModulus := modulus.Bytes()
+	aboveModulus := new(big.Int).Add(modulus, big.NewInt(1)).Bytes()
+	for _, msg := range [][]byte{atModulus, aboveModulus} {
+		assignment := NewEdDSACircuit(DefaultMessageLimbs)
+		if err := AssignMessage(assignment, msg); err != nil {
+			t.Fatalf("Error assigning message %x: %v", msg, err)
+		}
+		first := assignment.Message[0].(*big.Int)
+		second := assignment.Message[1].(*big.Int)
+		if first.Cmp(new(big.Int).SetBytes(msg[:MessageLimbSize])) != 0 || second.Cmp(new(big.Int).SetBytes(msg[MessageLimbSize:])) != 0 {
+			t.Fatalf("Message %x assigned as limbs %s, %s", msg, first, second)
+		}
+	}
+
+	// A signature over one therefore does not carry over to the other
+	sig, err := SignMessage(privateKey, atModulus, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error signing message:", err)
+	}
+	assert := test.NewAssert(t)
+	for msg, valid := range map[string]bool{string(atModulus): true, string(aboveModulus): false} {
+		assignment, err := NewAssignment(pubKey, sig, []byte(msg), DefaultMessageLimbs)
+		if err != nil {
+			t.Fatal("Error creating assignment:", err)
+		}
+		if valid {
+			assert.SolvingSucceeded(NewEdDSACircuit(DefaultMessageLimbs), assignment, test.WithCurves(ecc.BN254))
+		} else {
+			assert.SolvingFailed(NewEdDSACircuit(DefaultMessageLimbs), assignment, test.WithCurves(ecc.BN254))
+		}
+	}
+
+	// Messages too long for the limbs are rejected rather than wrapped
+	if err := AssignMessage(NewEdDSACircuit(1), atModulus); err == nil {
+		t.Fatal("Expected an error for a message longer than one limb")
+	}
+}
+
+func TestPublicInputs(t *testing.T) {
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal("Error creating private key:", err)
+	}
+	pubKey := privateKey.Public().Bytes()
+	msg := []byte{0xde, 0xad, 0xf0, 0x0d}
+	sig, err := SignMessage(privateKey, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error signing message:", err)
+	}
+	assignment, err := NewAssignment(pubKey, sig, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+
+	inputs, err := PublicInputs(ecc.BN254, assignment)
+	if err != nil {
+		t.Fatal("Error extracting public inputs:", err)
+	}
+
+	var a, r edwardsbn254.PointAffine
+	if _, err := a.SetBytes(pubKey); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.SetBytes(sig[:32]); err != nil {
+		t.Fatal(err)
+	}
+	want := []*big.Int{
+		a.X.BigInt(new(big.Int)),
+		a.Y.BigInt(new(big.Int)),
+		r.X.BigInt(new(big.Int)),
+		r.Y.BigInt(new(big.Int)),
+		new(big.Int).SetBytes(sig[32:]),
+		new(big.Int).SetBytes(msg),
+	}
+	for len(want) < 5+DefaultMessageLimbs {
+		want = append(want, new(big.Int))
+	}
+
+	if len(inputs) != len(want) {
+		t.Fatalf("Expected %d public inputs, got %d", len(want), len(inputs))
+	}
+	for i := range want {
+		if inputs[i].Cmp(want[i]) != 0 {
+			t.Fatalf("Public input %d: expected %s, got %s", i, want[i], inputs[i])
+		}
+	}
+}
+
+func TestSignatureToCircuit(t *testing.T) {
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal("Error creating private key:", err)
+	}
+	sig, err := SignMessage(privateKey, []byte{0xde, 0xad, 0xf0, 0x0d}, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error signing message:", err)
+	}
+
+	got, err := SignatureToCircuit(twistededwards.BN254, sig)
+	if err != nil {
+		t.Fatal("Error converting signature:", err)
+	}
+	var want eddsa.Signature
+	want.Assign(twistededwards.BN254, sig)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+
+	if _, err := SignatureToCircuit(twistededwards.BN254, sig[:32]); err == nil {
+		t.Fatal("Expected an error for a truncated signature")
+	}
+}
+
+func TestFullProveVerify(t *testing.T) {
+	valid, invalid := newTestAssignments(t)
+	assertProves(t, NewEdDSACircuit(DefaultMessageLimbs), valid)
+
+	ccs, err := CompileCircuit(ecc.BN254, backend.GROTH16)
+	if err != nil {
+		t.Fatal("Error compiling circuit:", err)
+	}
+	pk, _, err := SetupGroth16(ccs)
+	if err != nil {
+		t.Fatal("Error in setup:", err)
+	}
+	if _, err := ProveWithGroth16(ccs, pk, invalid); !errors.Is(err, ErrSignatureInvalid) {
+		t.Fatal("Expected ErrSignatureInvalid proving the tampered signature, got:", err)
+	}
+}
+
+// assertProves checks that assignment satisfies circuit with the test
+// solver, then runs the real Groth16 pipeline on BN254: it compiles circuit,
+// runs the setup, proves assignment and verifies the proof against its
+// public part, failing t at the first step that errors.
+func assertProves(t *testing.T, circuit, assignment frontend.Circuit) {
+	t.Helper()
+
+	assert := test.NewAssert(t)
+	assert.SolvingSucceeded(circuit, assignment, test.WithCurves(ecc.BN254))
+
+	ccs, err := compileCircuit(ecc.BN254, backend.GROTH16, circuit)
+	if err != nil {
+		t.Fatal("Error compiling circuit:", err)
+	}
+	pk, vk, err := SetupGroth16(ccs)
+	if err != nil {
+		t.Fatal("Error in setup:", err)
+	}
+	proof, err := ProveWithGroth16(ccs, pk, assignment)
+	if err != nil {
+		t.Fatal("Error proving:", err)
+	}
+	if err := VerifyWithGroth16(ccs, vk, proof, assignment); err != nil {
+		t.Fatal("Error verifying:", err)
+	}
+}
+
+// newTestAssignments signs a fixed message with a fresh key and returns an
+// assignment for the valid signature along with one for a tampered copy.
+func newTestAssignments(tb testing.TB) (valid, invalid *EdDSACircuit) {
+	tb.Helper()
+
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		tb.Fatal("Error creating private key:", err)
+	}
+	publicKey := privateKey.Public()
+
+	msg := []byte{0xde, 0xad, 0xf0, 0x0d}
+	signature, err := SignMessage(privateKey, msg, DefaultMessageLimbs)
+	if err != nil {
+		tb.Fatal("Error signing message:", err)
+	}
+
+	tamperedSignature := make([]byte, len(signature))
+	copy(tamperedSignature, signature)
+	tamperedSignature[0] ^= 0x01 // Flip a bit
+
+	valid, err = NewAssignment(publicKey.Bytes(), signature, msg, DefaultMessageLimbs)
+	if err != nil {
+		tb.Fatal("Error creating assignment:", err)
+	}
+	invalid, err = NewAssignment(publicKey.Bytes(), tamperedSignature, msg, DefaultMessageLimbs)
+	if err != nil {
+		tb.Fatal("Error creating assignment:", err)
+	}
+	return valid, invalid
+}
+
+func TestEdDSACircuitCurveMatrix(t *testing.T) {
+	// Supporting a new curve pair takes one more line here. Pairs the native
+	// side cannot sign for are skipped with the reason.
+	for _, tc := range []struct {
+		outer ecc.ID
+		inner twistededwards.ID
+	}{
+		{ecc.BN254, twistededwards.BN254},
+		{ecc.BLS12_381, twistededwards.BLS12_381},
+		{ecc.BLS12_381, twistededwards.BLS12_381_BANDERSNATCH},
+		{ecc.BLS12_377, twistededwards.BLS12_377},
+		{ecc.BLS24_315, twistededwards.BLS24_315},
+		{ecc.BW6_761, twistededwards.BW6_761},
+	} {
+		t.Run(curveName(tc.outer)+"/"+innerCurveName(tc.inner), func(t *testing.T) {
+			if _, err := InnerCurve(tc.outer); err != nil {
+				t.Skip("Skipping:", err)
+			}
+			privateKey, err := cryptoeddsa.New(tc.inner, rand.Reader)
+			if err != nil {
+				t.Skipf("Skipping %s keys: %v", innerCurveName(tc.inner), err)
+			}
+			msg := []byte{0xde, 0xad, 0xf0, 0x0d}
+			sig, err := SignMessageOn(tc.inner, HashMiMC, privateKey, msg, DefaultMessageLimbs)
+			if err != nil {
+				t.Skipf("Skipping %s keys over %s: %v", innerCurveName(tc.inner), curveName(tc.outer), err)
+			}
+			otherSig, err := SignMessageOn(tc.inner, HashMiMC, privateKey, []byte{0xba, 0xd0}, DefaultMessageLimbs)
+			if err != nil {
+				t.Fatal("Error signing message:", err)
+			}
+			pubKey := privateKey.Public().Bytes()
+
+			valid, err := NewAssignmentOn(tc.inner, pubKey, sig, msg, DefaultMessageLimbs)
+			if err != nil {
+				t.Fatal("Error creating assignment:", err)
+			}
+			// A valid signature, but of another message
+			invalid, err := NewAssignmentOn(tc.inner, pubKey, otherSig, msg, DefaultMessageLimbs)
+			if err != nil {
+				t.Fatal("Error creating assignment:", err)
+			}
+
+			assert := test.NewAssert(t)
+			for _, useCommitment := range []bool{false, true} {
+				circuit := NewEdDSACircuit(DefaultMessageLimbs)
+				circuit.Curve = tc.inner
+				circuit.UseCommitment = useCommitment
+				assert.SolvingSucceeded(circuit, valid, test.WithCurves(tc.outer))
+				assert.SolvingFailed(circuit, invalid, test.WithCurves(tc.outer))
+			}
+		})
+	}
 }
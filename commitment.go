@@ -0,0 +1,103 @@
+package main
+
+import (
+	"github.com/consensys/gnark/frontend"
+	tedwards "github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+)
+
+// lookupWindowBits is the width of the scalar windows lookupCurve processes
+// at once. Two bits need a table of 16 points; three bits would need 64,
+// whose construction costs more than the additions it saves.
+const lookupWindowBits = 2
+
+// lookupCurve is a twisted Edwards curve whose DoubleBaseScalarMul, the
+// bulk of eddsa.Verify, looks up the points to add from a table checked
+// with a log-derivative argument. The argument draws its challenge from a
+// commitment to the witness, so circuits using lookupCurve need a setup of
+// their own, and their Groth16 keys and proofs carry a Pedersen commitment.
+type lookupCurve struct {
+	tedwards.Curve
+}
+
+// DoubleBaseScalarMul returns [s1]p1 + [s2]p2. Both scalars are decomposed
+// into bits as tedwards.Curve does, but consumed lookupWindowBits at a
+// time: every window of s1 and s2 selects one of the precomputed sums
+// [i]p1 + [j]p2, so that a single addition replaces one per bit.
+func (c lookupCurve) DoubleBaseScalarMul(p1, p2 tedwards.Point, s1, s2 frontend.Variable) tedwards.Point {
+	api := c.API()
+	b1 := api.ToBinary(s1)
+	b2 := api.ToBinary(s2)
+
+	const size = 1 << lookupWindowBits
+	m1, m2 := c.multiples(p1, size), c.multiples(p2, size)
+	tableX, tableY := logderivlookup.New(api), logderivlookup.New(api)
+	for j := 0; j < size; j++ {
+		for i := 0; i < size; i++ {
+			var p tedwards.Point
+			switch {
+			case i == 0:
+				p = m2[j]
+			case j == 0:
+				p = m1[i]
+			default:
+				p = c.Add(m1[i], m2[j])
+			}
+			tableX.Insert(p.X)
+			tableY.Insert(p.Y)
+		}
+	}
+
+	nbWindows := (len(b1) + lookupWindowBits - 1) / lookupWindowBits
+	var res tedwards.Point
+	for k := nbWindows - 1; k >= 0; k-- {
+		index := api.Add(window(api, b1, k), api.Mul(window(api, b2, k), size))
+		p := tedwards.Point{X: tableX.Lookup(index)[0], Y: tableY.Lookup(index)[0]}
+		if k == nbWindows-1 {
+			res = p
+			continue
+		}
+		for i := 0; i < lookupWindowBits; i++ {
+			res = c.Double(res)
+		}
+		res = c.Add(res, p)
+	}
+	return res
+}
+
+// multiples returns [0]p, [1]p, ..., [n-1]p.
+func (c lookupCurve) multiples(p tedwards.Point, n int) []tedwards.Point {
+	m := make([]tedwards.Point, n)
+	m[0] = tedwards.Point{X: 0, Y: 1}
+	m[1] = p
+	for i := 2; i < n; i++ {
+		if i%2 == 0 {
+			m[i] = c.Double(m[i/2])
+		} else {
+			m[i] = c.Add(m[i-1], p)
+		}
+	}
+	return m
+}
+
+// window returns the value of the k-th window of lookupWindowBits bits of
+// the little-endian bits, treating bits past the end as zero.
+func window(api frontend.API, bits []frontend.Variable, k int) frontend.Variable {
+	var w frontend.Variable = 0
+	for i := lookupWindowBits - 1; i >= 0; i-- {
+		w = api.Mul(w, 2)
+		if j := k*lookupWindowBits + i; j < len(bits) {
+			w = api.Add(w, bits[j])
+		}
+	}
+	return w
+}
+
+// commitCurve returns curve wrapped in a lookupCurve if useCommitment is
+// set, and curve itself otherwise.
+func commitCurve(curve tedwards.Curve, useCommitment bool) tedwards.Curve {
+	if useCommitment {
+		return lookupCurve{curve}
+	}
+	return curve
+}
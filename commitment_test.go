@@ -0,0 +1,32 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/test"
+)
+
+func TestEdDSACircuitCommitment(t *testing.T) {
+	committed := NewEdDSACircuit(DefaultMessageLimbs)
+	committed.UseCommitment = true
+	for _, b := range []backend.ID{backend.GROTH16, backend.PLONK} {
+		plainCCS, err := compileCircuit(ecc.BN254, b, NewEdDSACircuit(DefaultMessageLimbs))
+		if err != nil {
+			t.Fatal("Error compiling circuit:", err)
+		}
+		committedCCS, err := compileCircuit(ecc.BN254, b, committed)
+		if err != nil {
+			t.Fatal("Error compiling circuit:", err)
+		}
+		t.Logf("%s constraints: %d without a commitment, %d with one", b, plainCCS.GetNbConstraints(), committedCCS.GetNbConstraints())
+		if committedCCS.GetNbConstraints() >= plainCCS.GetNbConstraints() {
+			t.Fatalf("Expected the commitment to reduce the %s constraint count", b)
+		}
+	}
+
+	valid, invalid := newTestAssignments(t)
+	assertProves(t, committed, valid)
+	test.NewAssert(t).SolvingFailed(committed, invalid, test.WithCurves(ecc.BN254))
+}
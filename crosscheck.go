@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	mathrand "math/rand/v2"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark-crypto/signature"
+	cryptoeddsa "github.com/consensys/gnark-crypto/signature/eddsa"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+)
+
+// CrossCheck signs n random messages of random lengths with n fresh BN254
+// keys and checks that native verification and solving the EdDSA circuit
+// reach the same decision, both for the genuine signatures and for tampered
+// messages, tampered signatures and wrong public keys. It returns an error
+// describing the first disagreement.
+func CrossCheck(n int) error {
+	ccs, err := CompileCircuit(ecc.BN254, backend.GROTH16)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < n; i++ {
+		privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+		if err != nil {
+			return err
+		}
+		other, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+		if err != nil {
+			return err
+		}
+		msg := make([]byte, 1+mathrand.IntN(DefaultMessageLimbs*MessageLimbSize))
+		if _, err := rand.Read(msg); err != nil {
+			return err
+		}
+		sig, err := SignMessage(privateKey, msg, DefaultMessageLimbs)
+		if err != nil {
+			return err
+		}
+
+		tamperedMsg := bytes.Clone(msg)
+		tamperedMsg[mathrand.IntN(len(tamperedMsg))] ^= 0x01
+		// Flipping the low bit of S keeps the encoding decodable
+		tamperedSig := bytes.Clone(sig)
+		tamperedSig[len(tamperedSig)-1] ^= 0x01
+
+		for _, c := range []struct {
+			name  string
+			pub   signature.PublicKey
+			sig   []byte
+			msg   []byte
+			valid bool
+		}{
+			{"genuine", privateKey.Public(), sig, msg, true},
+			{"tampered message", privateKey.Public(), sig, tamperedMsg, false},
+			{"tampered signature", privateKey.Public(), tamperedSig, msg, false},
+			{"wrong public key", other.Public(), sig, msg, false},
+		} {
+			native, err := verifyNative(c.pub, c.sig, c.msg)
+			if err != nil {
+				return fmt.Errorf("case %d (%s): %w", i, c.name, err)
+			}
+			inCircuit, err := solves(ccs, c.pub.Bytes(), c.sig, c.msg)
+			if err != nil {
+				return fmt.Errorf("case %d (%s): %w", i, c.name, err)
+			}
+			if native != inCircuit {
+				return fmt.Errorf("case %d (%s): native verification accepts: %t, circuit accepts: %t", i, c.name, native, inCircuit)
+			}
+			if native != c.valid {
+				return fmt.Errorf("case %d (%s): both sides accept: %t", i, c.name, native)
+			}
+		}
+	}
+	return nil
+}
+
+// verifyNative reports whether pub accepts sig over msg outside the circuit.
+// Signatures that do not even decode are rejected.
+func verifyNative(pub signature.PublicKey, sig, msg []byte) (bool, error) {
+	digest, err := HashMessage(msg, DefaultMessageLimbs)
+	if err != nil {
+		return false, err
+	}
+	ok, err := pub.Verify(sig, digest, mimc.NewMiMC())
+	return ok && err == nil, nil
+}
+
+// solves reports whether the assignment for pubKey, sig and msg satisfies
+// ccs.
+func solves(ccs constraint.ConstraintSystem, pubKey, sig, msg []byte) (bool, error) {
+	assignment, err := NewAssignment(pubKey, sig, msg, DefaultMessageLimbs)
+	if err != nil {
+		return false, err
+	}
+	w, err := frontend.NewWitness(assignment, ccs.Field())
+	if err != nil {
+		return false, fmt.Errorf("creating witness: %w", err)
+	}
+	return ccs.IsSolved(w) == nil, nil
+}
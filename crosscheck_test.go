@@ -0,0 +1,9 @@
+package main
+
+import "testing"
+
+func TestCrossCheck(t *testing.T) {
+	if err := CrossCheck(4); err != nil {
+		t.Fatal(err)
+	}
+}
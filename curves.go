@@ -0,0 +1,168 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+	"slices"
+	"strings"
+
+	"github.com/consensys/gnark"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	cryptohash "github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/frontend"
+)
+
+// curvePair is a SNARK curve together with the twisted Edwards curve defined
+// over its scalar field, on which EdDSA keys live.
+type curvePair struct {
+	outer ecc.ID
+	inner twistededwards.ID
+	mimc  cryptohash.Hash
+}
+
+// supportedCurves lists the curve pairs the circuits can be compiled for.
+var supportedCurves = []curvePair{
+	{ecc.BN254, twistededwards.BN254, cryptohash.MIMC_BN254},
+	{ecc.BLS12_381, twistededwards.BLS12_381, cryptohash.MIMC_BLS12_381},
+	{ecc.BLS12_377, twistededwards.BLS12_377, cryptohash.MIMC_BLS12_377},
+	{ecc.BLS24_315, twistededwards.BLS24_315, cryptohash.MIMC_BLS24_315},
+}
+
+// bandersnatch pairs BLS12-381 with Bandersnatch, the other twisted Edwards
+// curve defined over its scalar field. Circuits verify Bandersnatch
+// signatures when their Curve selects it, but InnerCurve keeps returning
+// Jubjub, so bandersnatch is not in supportedCurves.
+var bandersnatch = curvePair{ecc.BLS12_381, twistededwards.BLS12_381_BANDERSNATCH, cryptohash.MIMC_BLS12_381}
+
+// ErrBandersnatchEdDSA is returned when signing, hashing or assigning for
+// Bandersnatch. The pinned gnark-crypto v0.16.0 ships
+// ecc/bls12-381/bandersnatch/eddsa, but it is generated from the Jubjub
+// package and signs on Jubjub, so its signatures never verify in a
+// Bandersnatch circuit. Native support needs a gnark-crypto release with a
+// working Bandersnatch EdDSA.
+var ErrBandersnatchEdDSA = errors.New("bandersnatch EdDSA is not available: gnark-crypto v0.16.0 ecc/bls12-381/bandersnatch/eddsa signs on Jubjub")
+
+// ErrUnsupportedCurve is returned for a SNARK curve that is not in
+// supportedCurves, such as BW6-761: no twisted Edwards curve is defined over
+// its scalar field in gnark, so the circuits cannot be compiled for it. The
+// error lists the supported curves.
+var ErrUnsupportedCurve = errors.New("unsupported curve")
+
+// InnerCurve returns the twisted Edwards curve whose keys and signatures are
+// verified by circuits compiled over the scalar field of outer. Native
+// signing must use the same curve, since point encodings differ between
+// curves.
+func InnerCurve(outer ecc.ID) (twistededwards.ID, error) {
+	for _, p := range supportedCurves {
+		if p.outer == outer {
+			return p.inner, nil
+		}
+	}
+	return twistededwards.UNKNOWN, unsupportedCurveError(curveName(outer))
+}
+
+// unsupportedCurveError returns ErrUnsupportedCurve for the curve called
+// name, with the list of supported curves.
+func unsupportedCurveError(name string) error {
+	names := make([]string, len(supportedCurves))
+	for i, p := range supportedCurves {
+		names[i] = curveName(p.outer)
+	}
+	return fmt.Errorf("%w %s: no twisted Edwards curve for EdDSA keys is defined over its scalar field; supported curves are %s",
+		ErrUnsupportedCurve, name, strings.Join(names, ", "))
+}
+
+// outerCurve returns the SNARK curve whose scalar field is the base field of
+// inner.
+func outerCurve(inner twistededwards.ID) (ecc.ID, error) {
+	if inner == bandersnatch.inner {
+		return ecc.UNKNOWN, ErrBandersnatchEdDSA
+	}
+	for _, p := range supportedCurves {
+		if p.inner == inner {
+			return p.outer, nil
+		}
+	}
+	return ecc.UNKNOWN, fmt.Errorf("unsupported twisted Edwards curve ID %d", inner)
+}
+
+// compiledCurve returns the curve pair matching the field api compiles over,
+// with inner as its twisted Edwards curve. twistededwards.UNKNOWN selects
+// InnerCurve of the field.
+func compiledCurve(api frontend.API, inner twistededwards.ID) (curvePair, error) {
+	field := api.Compiler().Field()
+	for _, p := range slices.Concat(supportedCurves, []curvePair{bandersnatch}) {
+		if p.outer.ScalarField().Cmp(field) != 0 {
+			continue
+		}
+		if inner == twistededwards.UNKNOWN || inner == p.inner {
+			return p, nil
+		}
+	}
+	if inner != twistededwards.UNKNOWN {
+		return curvePair{}, fmt.Errorf("twisted Edwards curve ID %d is not defined over scalar field %s", inner, field)
+	}
+	if outer, err := fieldCurve(field); err == nil {
+		return curvePair{}, unsupportedCurveError(curveName(outer))
+	}
+	return curvePair{}, unsupportedCurveError("with scalar field " + field.String())
+}
+
+// curveName returns the conventional name of curve, such as "BLS12-381".
+func curveName(curve ecc.ID) string {
+	return strings.ToUpper(strings.ReplaceAll(curve.String(), "_", "-"))
+}
+
+// innerCurveName names inner after the SNARK curve whose scalar field it is
+// defined over, adding "Bandersnatch" to tell it from Jubjub.
+func innerCurveName(inner twistededwards.ID) string {
+	if inner == bandersnatch.inner {
+		return curveName(bandersnatch.outer) + " Bandersnatch"
+	}
+	for _, p := range supportedCurves {
+		if p.inner == inner {
+			return curveName(p.outer)
+		}
+	}
+	return fmt.Sprintf("twisted Edwards curve ID %d", inner)
+}
+
+// nativeHash returns a fresh native hasher for id over the scalar field in
+// which the coordinates of inner live.
+func nativeHash(inner twistededwards.ID, id HashID) (hash.Hash, error) {
+	switch id {
+	case HashMiMC:
+		for _, p := range supportedCurves {
+			if p.inner == inner {
+				return p.mimc.New(), nil
+			}
+		}
+		return nil, fmt.Errorf("unsupported twisted Edwards curve ID %d", inner)
+	case HashPoseidon2:
+		if inner != twistededwards.BN254 {
+			return nil, fmt.Errorf("%s is only available on BN254", id)
+		}
+		return newPoseidon2Hasher(), nil
+	default:
+		return nil, fmt.Errorf("unsupported hash %s", id)
+	}
+}
+
+// fieldBytes returns the size of a scalar field element of curve in bytes.
+func fieldBytes(curve ecc.ID) int {
+	return (curve.ScalarField().BitLen() + 7) / 8
+}
+
+// fieldCurve returns the curve, among all those gnark compiles for, whose
+// scalar field is field. It identifies the curve of a constraint system.
+func fieldCurve(field *big.Int) (ecc.ID, error) {
+	for _, curve := range gnark.Curves() {
+		if curve.ScalarField().Cmp(field) == 0 {
+			return curve, nil
+		}
+	}
+	return ecc.UNKNOWN, fmt.Errorf("unsupported scalar field %s", field)
+}
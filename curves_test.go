@@ -0,0 +1,157 @@
+package main
+
+import (
+	"crypto/rand"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	cryptoeddsa "github.com/consensys/gnark-crypto/signature/eddsa"
+	"github.com/consensys/gnark/backend"
+)
+
+func TestInnerCurve(t *testing.T) {
+	for outer, want := range map[ecc.ID]twistededwards.ID{
+		ecc.BN254:     twistededwards.BN254,
+		ecc.BLS12_381: twistededwards.BLS12_381,
+		ecc.BLS12_377: twistededwards.BLS12_377,
+		ecc.BLS24_315: twistededwards.BLS24_315,
+	} {
+		inner, err := InnerCurve(outer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if inner != want {
+			t.Fatalf("InnerCurve(%s) = %d, want %d", outer, inner, want)
+		}
+	}
+	if _, err := InnerCurve(ecc.BW6_761); !errors.Is(err, ErrUnsupportedCurve) {
+		t.Fatal("Expected ErrUnsupportedCurve, got:", err)
+	}
+}
+
+func TestUnsupportedCurve(t *testing.T) {
+	const want = "unsupported curve BW6-761: no twisted Edwards curve for EdDSA keys is defined over its scalar field; supported curves are BN254, BLS12-381, BLS12-377, BLS24-315"
+	if _, err := CompileCircuit(ecc.BW6_761, backend.GROTH16); !errors.Is(err, ErrUnsupportedCurve) || err.Error() != want {
+		t.Fatalf("CompileCircuit error = %v, want %q", err, want)
+	}
+	if _, err := NewVerifier(ecc.BW6_761); !errors.Is(err, ErrUnsupportedCurve) {
+		t.Fatal("Expected ErrUnsupportedCurve creating a verifier, got:", err)
+	}
+
+	// Circuits compiled directly fail in Define with the same error
+	_, err := compileCircuit(ecc.BW6_761, backend.GROTH16, NewEdDSACircuit(DefaultMessageLimbs))
+	if !errors.Is(err, ErrUnsupportedCurve) || !strings.Contains(err.Error(), want) {
+		t.Fatalf("compileCircuit error = %v, want %q", err, want)
+	}
+}
+
+func TestEdDSACircuitBLS12381(t *testing.T) {
+	testCircuitOn(t, ecc.BLS12_381)
+}
+
+func TestEdDSACircuitBLS24315(t *testing.T) {
+	testCircuitOn(t, ecc.BLS24_315)
+}
+
+func TestEdDSACircuitBandersnatch(t *testing.T) {
+	// The circuit side is in the pinned gnark, so it compiles over BLS12-381
+	// and only there
+	circuit := NewEdDSACircuit(DefaultMessageLimbs)
+	circuit.Curve = twistededwards.BLS12_381_BANDERSNATCH
+	if _, err := compileCircuit(ecc.BLS12_381, backend.GROTH16, circuit); err != nil {
+		t.Fatal("Error compiling Bandersnatch circuit:", err)
+	}
+	if _, err := compileCircuit(ecc.BN254, backend.GROTH16, circuit); err == nil {
+		t.Fatal("Expected an error compiling a Bandersnatch circuit over BN254")
+	}
+
+	// The native side is not in the pinned gnark-crypto
+	privateKey, err := cryptoeddsa.New(twistededwards.BLS12_381, rand.Reader)
+	if err != nil {
+		t.Fatal("Error generating private key:", err)
+	}
+	msg := []byte{0xde, 0xad, 0xf0, 0x0d}
+	if _, err := SignMessageOn(twistededwards.BLS12_381_BANDERSNATCH, HashMiMC, privateKey, msg, DefaultMessageLimbs); !errors.Is(err, ErrBandersnatchEdDSA) {
+		t.Fatal("Expected ErrBandersnatchEdDSA signing, got:", err)
+	}
+	if _, err := NewKeyFromSeed(twistededwards.BLS12_381_BANDERSNATCH, []byte("seed")); !errors.Is(err, ErrBandersnatchEdDSA) {
+		t.Fatal("Expected ErrBandersnatchEdDSA generating a key, got:", err)
+	}
+}
+
+func TestAssignmentCurveMismatch(t *testing.T) {
+	privateKey, err := cryptoeddsa.New(twistededwards.BLS12_381, rand.Reader)
+	if err != nil {
+		t.Fatal("Error generating private key:", err)
+	}
+	pubKey := privateKey.Public().Bytes()
+	msg := []byte{0xde, 0xad, 0xf0, 0x0d}
+	sig, err := SignMessageOn(twistededwards.BLS12_381, HashMiMC, privateKey, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error signing message:", err)
+	}
+	assignment, err := NewAssignmentOn(twistededwards.BLS12_381, pubKey, sig, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+
+	const want = "public key curve BLS12-381 does not match circuit curve BN254"
+	if _, err := IsSatisfied(ecc.BN254, assignment); err == nil || err.Error() != want {
+		t.Fatalf("IsSatisfied error = %v, want %q", err, want)
+	}
+	ccs, err := CompileCircuit(ecc.BN254, backend.GROTH16)
+	if err != nil {
+		t.Fatal("Error compiling circuit:", err)
+	}
+	if _, err := ProveWithGroth16(ccs, nil, assignment); err == nil || err.Error() != want {
+		t.Fatalf("ProveWithGroth16 error = %v, want %q", err, want)
+	}
+}
+
+// testCircuitOn signs a message with a key on InnerCurve(outer), then
+// proves and verifies the signature with Groth16 over outer.
+func testCircuitOn(t *testing.T, outer ecc.ID) {
+	inner, err := InnerCurve(outer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKey, err := cryptoeddsa.New(inner, rand.Reader)
+	if err != nil {
+		t.Fatal("Error generating private key:", err)
+	}
+	pubKey := privateKey.Public().Bytes()
+	msg := []byte{0xde, 0xad, 0xf0, 0x0d}
+	sig, err := SignMessageOn(inner, HashMiMC, privateKey, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error signing message:", err)
+	}
+	if err := ValidatePublicKey(inner, pubKey); err != nil {
+		t.Fatal("Error validating public key:", err)
+	}
+	assignment, err := NewAssignmentOn(inner, pubKey, sig, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+
+	ccs, err := CompileCircuit(outer, backend.GROTH16)
+	if err != nil {
+		t.Fatal("Error compiling circuit:", err)
+	}
+	pk, vk, err := SetupGroth16(ccs)
+	if err != nil {
+		t.Fatal("Error in setup:", err)
+	}
+	proof, err := ProveWithGroth16(ccs, pk, assignment)
+	if err != nil {
+		t.Fatal("Error proving:", err)
+	}
+	if err := VerifyWithGroth16(ccs, vk, proof, assignment); err != nil {
+		t.Fatal("Error verifying:", err)
+	}
+	if err := VerifyProof(vk, proof, pubKey, sig, msg); err != nil {
+		t.Fatal("Error verifying from raw inputs:", err)
+	}
+}
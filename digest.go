@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark-crypto/signature"
+	"github.com/consensys/gnark/frontend"
+	tedwards "github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/signature/eddsa"
+)
+
+// ErrDigestOutOfRange is returned for digests that are not the canonical
+// encoding of a BN254 scalar field element. Use ReduceDigest to map them
+// into the field explicitly.
+var ErrDigestOutOfRange = errors.New("digest is not below the BN254 scalar field modulus")
+
+// DigestEdDSACircuit verifies a signature over a digest computed outside the
+// circuit, for instance by SignDigest callers hashing their messages
+// elsewhere. It skips the in-circuit message hashing of EdDSACircuit and is
+// correspondingly smaller; binding the digest to a message is left to the
+// caller.
+type DigestEdDSACircuit struct {
+	PublicKey eddsa.PublicKey   `gnark:",public"`
+	Signature eddsa.Signature   `gnark:",public"`
+	Digest    frontend.Variable `gnark:",public"`
+}
+
+// SignDigest signs a BN254 digest as is, with MiMC as the signature hash.
+// The digest must be at most fr.Bytes big-endian bytes encoding a value
+// below the field modulus; anything else fails with ErrDigestOutOfRange
+// rather than being reduced silently.
+func SignDigest(priv signature.Signer, digest []byte) ([]byte, error) {
+	if err := checkDigest(digest); err != nil {
+		return nil, err
+	}
+	return priv.Sign(digest, mimc.NewMiMC())
+}
+
+// ReduceDigest maps an arbitrary big-endian digest into the BN254 scalar
+// field by reducing it modulo the field order, returning fr.Bytes bytes that
+// SignDigest accepts. Distinct digests may reduce to the same value.
+func ReduceDigest(digest []byte) []byte {
+	var e fr.Element
+	e.SetBigInt(new(big.Int).SetBytes(digest))
+	b := e.Bytes()
+	return b[:]
+}
+
+// NewDigestAssignment returns an assignment of DigestEdDSACircuit for a
+// BN254 public key and a signature produced by SignDigest over digest.
+func NewDigestAssignment(pubKey, sig, digest []byte) (*DigestEdDSACircuit, error) {
+	if err := checkDigest(digest); err != nil {
+		return nil, err
+	}
+	assignment := &DigestEdDSACircuit{Digest: new(big.Int).SetBytes(digest)}
+	if err := assignEncodings(twistededwards.BN254, &assignment.PublicKey, &assignment.Signature, pubKey, sig); err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
+
+func checkDigest(digest []byte) error {
+	if len(digest) > fr.Bytes {
+		return fmt.Errorf("%w: %d bytes", ErrDigestOutOfRange, len(digest))
+	}
+	if new(big.Int).SetBytes(digest).Cmp(fr.Modulus()) >= 0 {
+		return ErrDigestOutOfRange
+	}
+	return nil
+}
+
+// Define implements the circuit for EdDSA verification over a digest
+func (circuit *DigestEdDSACircuit) Define(api frontend.API) error {
+	curve, err := tedwards.NewEdCurve(api, twistededwards.BN254)
+	if err != nil {
+		return err
+	}
+	hash, err := newMiMCFieldHasher(api)
+	if err != nil {
+		return err
+	}
+	return eddsa.Verify(curve, circuit.Signature, circuit.Digest, circuit.PublicKey, hash)
+}
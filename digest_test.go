@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	cryptoeddsa "github.com/consensys/gnark-crypto/signature/eddsa"
+	"github.com/consensys/gnark/test"
+)
+
+func TestDigestEdDSACircuit(t *testing.T) {
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal("Error creating private key:", err)
+	}
+	pubKey := privateKey.Public().Bytes()
+
+	// The digest is computed outside of this package
+	digest, err := HashMessage([]byte{0xde, 0xad, 0xf0, 0x0d}, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error hashing message:", err)
+	}
+	sig, err := SignDigest(privateKey, digest)
+	if err != nil {
+		t.Fatal("Error signing digest:", err)
+	}
+	assignment, err := NewDigestAssignment(pubKey, sig, digest)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+
+	// The same signature must not verify over another digest
+	otherDigest := bytes.Clone(digest)
+	otherDigest[len(otherDigest)-1] ^= 0x01
+	invalidAssignment, err := NewDigestAssignment(pubKey, sig, otherDigest)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+
+	assert := test.NewAssert(t)
+	assert.SolvingSucceeded(&DigestEdDSACircuit{}, assignment, test.WithCurves(ecc.BN254))
+	assert.SolvingFailed(&DigestEdDSACircuit{}, invalidAssignment, test.WithCurves(ecc.BN254))
+}
+
+func TestSignDigestOutOfRange(t *testing.T) {
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal("Error creating private key:", err)
+	}
+
+	// The modulus itself and any longer digest are rejected
+	for _, digest := range [][]byte{fr.Modulus().FillBytes(make([]byte, fr.Bytes)), bytes.Repeat([]byte{0xff}, fr.Bytes+1)} {
+		if _, err := SignDigest(privateKey, digest); !errors.Is(err, ErrDigestOutOfRange) {
+			t.Fatalf("Expected ErrDigestOutOfRange for a %d-byte digest, got: %v", len(digest), err)
+		}
+		if _, err := NewDigestAssignment(privateKey.Public().Bytes(), make([]byte, 2*fr.Bytes), digest); !errors.Is(err, ErrDigestOutOfRange) {
+			t.Fatalf("Expected ErrDigestOutOfRange for a %d-byte digest, got: %v", len(digest), err)
+		}
+	}
+
+	// Reducing them explicitly makes them signable, and the signature
+	// verifies in the circuit
+	reduced := ReduceDigest(bytes.Repeat([]byte{0xff}, fr.Bytes))
+	sig, err := SignDigest(privateKey, reduced)
+	if err != nil {
+		t.Fatal("Error signing reduced digest:", err)
+	}
+	assignment, err := NewDigestAssignment(privateKey.Public().Bytes(), sig, reduced)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+	assert := test.NewAssert(t)
+	assert.SolvingSucceeded(&DigestEdDSACircuit{}, assignment, test.WithCurves(ecc.BN254))
+}
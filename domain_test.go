@@ -0,0 +1,70 @@
+package main
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	cryptoeddsa "github.com/consensys/gnark-crypto/signature/eddsa"
+	"github.com/consensys/gnark/test"
+)
+
+func TestDomainEdDSACircuit(t *testing.T) {
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal("Error creating private key:", err)
+	}
+
+	fields := DomainFields{
+		Action:  big.NewInt(1),
+		Subject: big.NewInt(42),
+		Value:   big.NewInt(1000),
+	}
+	sig, err := SignDomainMessage(privateKey, "payments/v1", fields)
+	if err != nil {
+		t.Fatal("Error signing message:", err)
+	}
+	assignment, err := NewDomainAssignment(privateKey.Public().Bytes(), sig, fields)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+
+	assert := test.NewAssert(t)
+	assert.SolvingSucceeded(&DomainEdDSACircuit{Domain: "payments/v1"}, assignment, test.WithCurves(ecc.BN254))
+
+	// The same signature and fields must not verify under another domain
+	assert.SolvingFailed(&DomainEdDSACircuit{Domain: "payments/v2"}, assignment, test.WithCurves(ecc.BN254))
+}
+
+func TestNewDomainAssignmentRejectsMalformedInputs(t *testing.T) {
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal("Error creating private key:", err)
+	}
+	fields := DomainFields{Action: big.NewInt(1), Subject: big.NewInt(42), Value: big.NewInt(1000)}
+	sig, err := SignDomainMessage(privateKey, "payments/v1", fields)
+	if err != nil {
+		t.Fatal("Error signing message:", err)
+	}
+	pubKey := privateKey.Public().Bytes()
+
+	if _, err := NewDomainAssignment(pubKey[:16], sig, fields); err == nil {
+		t.Fatal("Expected an error for a short public key")
+	}
+	if _, err := NewDomainAssignment(pubKey, sig[:len(sig)-1], fields); err == nil {
+		t.Fatal("Expected an error for a short signature")
+	}
+}
+
+func TestHashDomainMessageRejectsOutOfFieldValues(t *testing.T) {
+	fields := DomainFields{
+		Action:  big.NewInt(1),
+		Subject: big.NewInt(42),
+		Value:   ecc.BN254.ScalarField(),
+	}
+	if _, err := HashDomainMessage("payments/v1", fields); err == nil {
+		t.Fatal("Expected an error for a value equal to the modulus")
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	cryptoeddsa "github.com/consensys/gnark-crypto/signature/eddsa"
+)
+
+// ed25519ConversionDST separates the circuit keys ConvertForCircuit derives
+// from any other use of an Ed25519 seed.
+const ed25519ConversionDST = "eddsa-gnark ed25519 conversion"
+
+// ErrEd25519Signature is returned when a public key and signature given for
+// the circuit are a valid Ed25519 signature of the message. Ed25519 signs on
+// edwards25519 and hashes with SHA-512, while the circuit verifies EdDSA on
+// the twisted Edwards curve embedded in the SNARK field with MiMC: the
+// curves have unrelated base fields, so an Ed25519 key is not a point the
+// circuit can use, and SHA-512 would cost hundreds of thousands of
+// constraints to emulate. Such a signature never verifies in the circuit.
+// Use ConvertForCircuit to sign the message again with a circuit key.
+var ErrEd25519Signature = errors.New("Ed25519 signatures cannot be verified by the EdDSA circuit, which verifies MiMC EdDSA on the curve embedded in the SNARK field; see ConvertForCircuit")
+
+// CheckNotEd25519 returns ErrEd25519Signature if pubKey and sig are a valid
+// Ed25519 signature of msg. Both encodings are 32 and 64 bytes, like those
+// of BN254 keys and signatures, so they cannot be told apart by size; a
+// signature that verifies under Ed25519 is almost surely not meant for the
+// circuit.
+func CheckNotEd25519(pubKey, sig, msg []byte) error {
+	if len(pubKey) != ed25519.PublicKeySize || len(sig) != ed25519.SignatureSize {
+		return nil
+	}
+	if ed25519.Verify(pubKey, msg, sig) {
+		return ErrEd25519Signature
+	}
+	return nil
+}
+
+// ConvertForCircuit is the way to prove knowledge of a message signed with
+// Ed25519: it signs msg again, with SignMessage, under a BN254 circuit key
+// derived deterministically from the seed of priv, and returns that key's
+// public key with the signature. The same Ed25519 key always yields the same
+// circuit key.
+//
+// The circuit key is a different key from the Ed25519 one, and nothing in
+// the circuit ties the two together. A verifier that knows signers by their
+// Ed25519 keys must learn the circuit key through a channel it trusts, such
+// as an Ed25519 signature of the returned public key.
+func ConvertForCircuit(priv ed25519.PrivateKey, msg []byte) (pubKey, sig []byte, err error) {
+	if len(priv) != ed25519.PrivateKeySize {
+		return nil, nil, fmt.Errorf("Ed25519 private key must be %d bytes, got %d", ed25519.PrivateKeySize, len(priv))
+	}
+	// Unlike the seeds NewKeyFromSeed is meant for, an Ed25519 seed is a
+	// uniformly random secret, so deriving a production key from it is safe
+	circuitKey, err := cryptoeddsa.New(twistededwards.BN254, newSeedReader(append([]byte(ed25519ConversionDST), priv.Seed()...)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("deriving circuit key: %w", err)
+	}
+	sig, err = SignMessage(circuitKey, msg, DefaultMessageLimbs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return circuitKey.Public().Bytes(), sig, nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+func TestEd25519Signatures(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub := priv.Public().(ed25519.PublicKey)
+	msg := []byte{0xde, 0xad, 0xf0, 0x0d}
+	edSig := ed25519.Sign(priv, msg)
+
+	if err := CheckNotEd25519(pub, edSig, msg); !errors.Is(err, ErrEd25519Signature) {
+		t.Fatal("Expected ErrEd25519Signature, got:", err)
+	}
+	v, err := NewVerifier(ecc.BN254)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := v.Prove(pub, edSig, msg); !errors.Is(err, ErrEd25519Signature) {
+		t.Fatal("Expected proving an Ed25519 signature to fail with ErrEd25519Signature, got:", err)
+	}
+
+	// Re-signing with the derived circuit key proves and verifies
+	pubKey, sig, err := ConvertForCircuit(priv, msg)
+	if err != nil {
+		t.Fatal("Error converting signature:", err)
+	}
+	if err := CheckNotEd25519(pubKey, sig, msg); err != nil {
+		t.Fatal("Expected a circuit signature not to be taken for Ed25519:", err)
+	}
+	proof, err := v.Prove(pubKey, sig, msg)
+	if err != nil {
+		t.Fatal("Error proving converted signature:", err)
+	}
+	if err := v.Verify(proof, pubKey, sig, msg); err != nil {
+		t.Fatal("Error verifying converted signature:", err)
+	}
+
+	// The circuit key only depends on the Ed25519 key
+	again, _, err := ConvertForCircuit(priv, []byte{0x01})
+	if err != nil {
+		t.Fatal("Error converting signature:", err)
+	}
+	if !bytes.Equal(again, pubKey) {
+		t.Fatal("Expected the same Ed25519 key to yield the same circuit key")
+	}
+}
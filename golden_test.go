@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/test"
+)
+
+var updateGolden = flag.Bool("update-golden", false, "regenerate testdata/golden_vector.json")
+
+var goldenVectorPath = filepath.Join("testdata", "golden_vector.json")
+
+// goldenVector is a signature made with a key derived from Seed, hex
+// encoded. Any change in how keys, messages or signatures are encoded makes
+// TestGoldenVector fail against the committed copy.
+type goldenVector struct {
+	Seed       string `json:"seed"`
+	PrivateKey string `json:"privateKey"`
+	PublicKey  string `json:"publicKey"`
+	Message    string `json:"message"`
+	Signature  string `json:"signature"`
+}
+
+// newGoldenVector derives the key from the fixed seed and signs the fixed
+// message with it.
+func newGoldenVector(tb testing.TB) goldenVector {
+	tb.Helper()
+
+	seed := []byte("eddsa-gnark golden vector")
+	msg := []byte("the quick brown fox jumps over the lazy dog")
+	privateKey, err := NewKeyFromSeed(twistededwards.BN254, seed)
+	if err != nil {
+		tb.Fatal("Error deriving private key:", err)
+	}
+	sig, err := SignMessage(privateKey, msg, DefaultMessageLimbs)
+	if err != nil {
+		tb.Fatal("Error signing message:", err)
+	}
+	return goldenVector{
+		Seed:       hex.EncodeToString(seed),
+		PrivateKey: hex.EncodeToString(privateKey.Bytes()),
+		PublicKey:  hex.EncodeToString(privateKey.Public().Bytes()),
+		Message:    hex.EncodeToString(msg),
+		Signature:  hex.EncodeToString(sig),
+	}
+}
+
+// TestGoldenVector checks that the committed vector is still reproduced
+// bit for bit, and still verifies natively and in-circuit. Run it with
+// -update-golden after a deliberate encoding change to regenerate the file.
+func TestGoldenVector(t *testing.T) {
+	got := newGoldenVector(t)
+	if *updateGolden {
+		data, err := json.MarshalIndent(got, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(goldenVectorPath, append(data, '\n'), 0o644); err != nil {
+			t.Fatal("Error writing golden vector:", err)
+		}
+	}
+
+	data, err := os.ReadFile(goldenVectorPath)
+	if err != nil {
+		t.Fatal("Error reading golden vector:", err)
+	}
+	var want goldenVector
+	if err := json.Unmarshal(data, &want); err != nil {
+		t.Fatal("Error decoding golden vector:", err)
+	}
+	if got != want {
+		t.Fatalf("Encodings changed since the golden vector was generated:\ngot  %+v\nwant %+v", got, want)
+	}
+
+	decode := func(name, s string) []byte {
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			t.Fatalf("Error decoding %s: %v", name, err)
+		}
+		return b
+	}
+	seed := decode("seed", want.Seed)
+	pubKey := decode("publicKey", want.PublicKey)
+	msg := decode("message", want.Message)
+	sig := decode("signature", want.Signature)
+
+	privateKey, err := NewKeyFromSeed(twistededwards.BN254, seed)
+	if err != nil {
+		t.Fatal("Error deriving private key:", err)
+	}
+	if !bytes.Equal(privateKey.Public().Bytes(), pubKey) {
+		t.Fatal("Seed no longer derives the golden public key")
+	}
+	ok, err := verifyNative(privateKey.Public(), sig, msg)
+	if err != nil {
+		t.Fatal("Error verifying natively:", err)
+	}
+	if !ok {
+		t.Fatal("Golden signature does not verify natively")
+	}
+
+	assignment, err := NewAssignment(pubKey, sig, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+	assert := test.NewAssert(t)
+	assert.SolvingSucceeded(NewEdDSACircuit(DefaultMessageLimbs), assignment, test.WithCurves(ecc.BN254))
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	cryptohash "github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/frontend"
+	stdhash "github.com/consensys/gnark/std/hash"
+	stdmimc "github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/selector"
+)
+
+// HashID selects the hash function used both to sign natively and to verify
+// in-circuit. The two sides must agree or eddsa.Verify fails.
+type HashID int
+
+const (
+	// HashMiMC is the MiMC hash over the BN254 scalar field.
+	HashMiMC HashID = iota
+	// HashPoseidon2 is a Poseidon2 hash over the BN254 scalar field.
+	HashPoseidon2
+)
+
+// SupportedHashes lists every HashID that NewHashFunc accepts.
+var SupportedHashes = []HashID{HashMiMC, HashPoseidon2}
+
+func (id HashID) String() string {
+	switch id {
+	case HashMiMC:
+		return "mimc"
+	case HashPoseidon2:
+		return "poseidon2"
+	default:
+		return fmt.Sprintf("HashID(%d)", int(id))
+	}
+}
+
+// HashFunc pairs the native hash used for signing with the matching
+// in-circuit hash used for verification.
+type HashFunc struct {
+	// Native returns a fresh hasher for signing and native verification of
+	// BN254 keys. For other curves, use SignMessageOn and HashMessageOn.
+	Native func() hash.Hash
+	// Circuit returns a fresh in-circuit hasher.
+	Circuit func(api frontend.API) (stdhash.FieldHasher, error)
+}
+
+// NewHashFunc returns the native and in-circuit constructors for id.
+func NewHashFunc(id HashID) (HashFunc, error) {
+	switch id {
+	case HashMiMC:
+		return HashFunc{Native: newMiMCHasher, Circuit: newMiMCFieldHasher}, nil
+	case HashPoseidon2:
+		return HashFunc{Native: newPoseidon2Hasher, Circuit: newPoseidon2FieldHasher}, nil
+	default:
+		return HashFunc{}, fmt.Errorf("unsupported hash %s", id)
+	}
+}
+
+func newMiMCHasher() hash.Hash {
+	return mimc.NewMiMC()
+}
+
+func newMiMCFieldHasher(api frontend.API) (stdhash.FieldHasher, error) {
+	h, err := stdmimc.NewMiMC(api)
+	if err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+// selectedFieldHasher is an in-circuit hasher computing the digest under
+// every hash of SupportedHashes and returning the one selected by id, the
+// HashID as a variable. Each write goes to all the hashes, so the cost is
+// the sum of theirs. An id outside SupportedHashes fails the proof.
+type selectedFieldHasher struct {
+	api     frontend.API
+	id      frontend.Variable
+	hashers []stdhash.FieldHasher
+}
+
+// newSelectedFieldHasher returns a selectedFieldHasher choosing among
+// hashers, indexed by HashID, with id.
+func newSelectedFieldHasher(api frontend.API, id frontend.Variable, hashers []stdhash.FieldHasher) *selectedFieldHasher {
+	return &selectedFieldHasher{api: api, id: id, hashers: hashers}
+}
+
+func (h *selectedFieldHasher) Write(data ...frontend.Variable) {
+	for _, hasher := range h.hashers {
+		hasher.Write(data...)
+	}
+}
+
+func (h *selectedFieldHasher) Sum() frontend.Variable {
+	sums := make([]frontend.Variable, len(h.hashers))
+	for i, hasher := range h.hashers {
+		sums[i] = hasher.Sum()
+	}
+	return selector.Mux(h.api, h.id, sums...)
+}
+
+func (h *selectedFieldHasher) Reset() {
+	for _, hasher := range h.hashers {
+		hasher.Reset()
+	}
+}
+
+// keyedMiMC is a native MiMC hasher whose state starts from key instead of
+// zero, and returns to it on Reset.
+type keyedMiMC struct {
+	hash.Hash
+	key []byte
+}
+
+// newKeyedMiMCHasher returns a native MiMC hasher over the scalar field in
+// which the coordinates of inner live, keyed with the big-endian field
+// element key.
+func newKeyedMiMCHasher(inner twistededwards.ID, key []byte) (hash.Hash, error) {
+	h, err := nativeHash(inner, HashMiMC)
+	if err != nil {
+		return nil, err
+	}
+	state := make([]byte, h.Size())
+	if len(key) > len(state) {
+		return nil, fmt.Errorf("mimc key must be at most %d bytes, got %d", len(state), len(key))
+	}
+	copy(state[len(state)-len(key):], key)
+
+	k := &keyedMiMC{Hash: h, key: state}
+	if err := k.setKey(); err != nil {
+		return nil, fmt.Errorf("invalid mimc key: %w", err)
+	}
+	return k, nil
+}
+
+func (h *keyedMiMC) setKey() error {
+	return h.Hash.(cryptohash.StateStorer).SetState(h.key)
+}
+
+func (h *keyedMiMC) Reset() {
+	h.Hash.Reset()
+	// The key was accepted by newKeyedMiMCHasher, so it cannot fail here
+	_ = h.setKey()
+}
+
+// keyedMiMCFieldHasher is the in-circuit counterpart of keyedMiMC.
+type keyedMiMCFieldHasher struct {
+	stdmimc.MiMC
+	key frontend.Variable
+}
+
+// newKeyedMiMCFieldHasher returns an in-circuit MiMC hasher keyed with the
+// big-endian field element key, which must be below the field modulus.
+func newKeyedMiMCFieldHasher(api frontend.API, key []byte) (stdhash.FieldHasher, error) {
+	k := new(big.Int).SetBytes(key)
+	if k.Cmp(api.Compiler().Field()) >= 0 {
+		return nil, errors.New("mimc key is not below the field modulus")
+	}
+	m, err := stdmimc.NewMiMC(api)
+	if err != nil {
+		return nil, err
+	}
+	h := &keyedMiMCFieldHasher{MiMC: m, key: k}
+	h.Reset()
+	return h, nil
+}
+
+func (h *keyedMiMCFieldHasher) Reset() {
+	h.MiMC.Reset()
+	// A freshly reset hasher always accepts a single state element
+	_ = h.MiMC.SetState([]frontend.Variable{h.key})
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	cryptoeddsa "github.com/consensys/gnark-crypto/signature/eddsa"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/test"
+)
+
+func TestHashRoundTrip(t *testing.T) {
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal("Error creating private key:", err)
+	}
+	publicKey := privateKey.Public()
+	msg := []byte("a message signed under every supported hash")
+
+	for _, id := range SupportedHashes {
+		t.Run(id.String(), func(t *testing.T) {
+			hashFunc, err := NewHashFunc(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			signature, err := SignMessageWith(id, privateKey, msg, DefaultMessageLimbs)
+			if err != nil {
+				t.Fatal("Error signing message:", err)
+			}
+
+			// Verify the signature (outside the circuit)
+			digest, err := HashMessageWith(id, msg, DefaultMessageLimbs)
+			if err != nil {
+				t.Fatal("Error hashing message:", err)
+			}
+			isValid, err := publicKey.Verify(signature, digest, hashFunc.Native())
+			if err != nil {
+				t.Fatal("Error verifying signature:", err)
+			}
+			if !isValid {
+				t.Fatal("Invalid signature")
+			}
+
+			// Verify the signature inside the circuit
+			assignment, err := NewAssignment(publicKey.Bytes(), signature, msg, DefaultMessageLimbs)
+			if err != nil {
+				t.Fatal("Error creating assignment:", err)
+			}
+			circuit := NewEdDSACircuit(DefaultMessageLimbs)
+			circuit.Hash = id
+
+			assert := test.NewAssert(t)
+			assert.SolvingSucceeded(circuit, assignment, test.WithCurves(ecc.BN254))
+
+			// A circuit configured with any other hash must reject it
+			for _, other := range SupportedHashes {
+				if other == id {
+					continue
+				}
+				circuit := NewEdDSACircuit(DefaultMessageLimbs)
+				circuit.Hash = other
+				assert.SolvingFailed(circuit, assignment, test.WithCurves(ecc.BN254))
+			}
+		})
+	}
+}
+
+func TestNewHashFuncUnsupported(t *testing.T) {
+	if _, err := NewHashFunc(HashID(-1)); err == nil {
+		t.Fatal("Expected an error for an unsupported hash")
+	}
+}
+
+func TestCustomCircuitHash(t *testing.T) {
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal("Error creating private key:", err)
+	}
+	msg := []byte("a message signed under a custom hash")
+
+	// Poseidon2 is plugged in through NewHash rather than selected by Hash
+	signature, err := SignMessageUsing(twistededwards.BN254, newPoseidon2Hasher, privateKey, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error signing message:", err)
+	}
+	assignment, err := NewAssignment(privateKey.Public().Bytes(), signature, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+
+	// gnark's test engine cannot clone a circuit holding a function, so the
+	// signature is proved end to end instead
+	circuit := NewEdDSACircuit(DefaultMessageLimbs)
+	circuit.NewHash = newPoseidon2FieldHasher
+	ccs, err := compileCircuit(ecc.BN254, backend.GROTH16, circuit)
+	if err != nil {
+		t.Fatal("Error compiling circuit:", err)
+	}
+	pk, vk, err := SetupGroth16(ccs)
+	if err != nil {
+		t.Fatal("Error in setup:", err)
+	}
+	proof, err := ProveWithGroth16(ccs, pk, assignment)
+	if err != nil {
+		t.Fatal("Error generating proof:", err)
+	}
+	if err := VerifyWithGroth16(ccs, vk, proof, assignment); err != nil {
+		t.Fatal("Error verifying proof:", err)
+	}
+
+	// The default MiMC circuit rejects it
+	assert := test.NewAssert(t)
+	assert.SolvingFailed(NewEdDSACircuit(DefaultMessageLimbs), assignment, test.WithCurves(ecc.BN254))
+
+	// A custom hash cannot be keyed
+	circuit.MiMCKey = []byte{0x01}
+	if _, err := compileCircuit(ecc.BN254, backend.GROTH16, circuit); err == nil {
+		t.Fatal("Expected an error compiling a keyed custom hash")
+	}
+}
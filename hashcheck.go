@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	edwardsbls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/twistededwards"
+	eddsabls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/twistededwards/eddsa"
+	edwardsbls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/twistededwards"
+	eddsabls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/twistededwards/eddsa"
+	edwardsbls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/twistededwards"
+	eddsabls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/twistededwards/eddsa"
+	edwardsbn254 "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	eddsabn254 "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards/eddsa"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark-crypto/signature"
+)
+
+// ErrHashMismatch reports a signature that does not verify under the hash
+// the circuit recomputes, but does under another of SupportedHashes: it was
+// most likely signed for a circuit configured with that hash.
+var ErrHashMismatch = errors.New("signature was made with another hash")
+
+// CheckSignatureHash verifies natively that sig signs msg, split into
+// nbLimbs limbs, under pubKey on inner with the hash selected by id, as the
+// circuit does. Proving a signature made under another hash only fails with
+// an unsatisfied constraint, so on failure the other SupportedHashes
+// available on inner are tried: if one verifies, the error wraps
+// ErrHashMismatch and names it. Otherwise the error wraps
+// ErrSignatureInvalid.
+func CheckSignatureHash(inner twistededwards.ID, id HashID, pubKey, sig, msg []byte, nbLimbs int) error {
+	pub, err := parsePublicKey(inner, pubKey)
+	if err != nil {
+		return fmt.Errorf("invalid public key: %w", err)
+	}
+	ok, err := verifiesUnder(inner, id, pub, sig, msg, nbLimbs)
+	if err != nil {
+		return err
+	}
+	if ok {
+		return nil
+	}
+	for _, other := range SupportedHashes {
+		if other == id {
+			continue
+		}
+		// Hashes unavailable on inner, such as Poseidon2 off BN254, cannot
+		// have been used
+		if ok, err := verifiesUnder(inner, other, pub, sig, msg, nbLimbs); err == nil && ok {
+			return fmt.Errorf("%w: %w: it does not verify under %s but does under %s, the likely intended hash",
+				ErrSignatureInvalid, ErrHashMismatch, id, other)
+		}
+	}
+	return fmt.Errorf("%w: it does not verify natively under %s or any other supported hash", ErrSignatureInvalid, id)
+}
+
+// verifiesUnder reports whether pub accepts sig over the digest of msg under
+// the hash selected by id. Signatures that do not decode are rejected.
+func verifiesUnder(inner twistededwards.ID, id HashID, pub signature.PublicKey, sig, msg []byte, nbLimbs int) (bool, error) {
+	digest, err := HashMessageOn(inner, id, msg, nbLimbs)
+	if err != nil {
+		return false, err
+	}
+	hFunc, err := nativeHash(inner, id)
+	if err != nil {
+		return false, err
+	}
+	ok, err := pub.Verify(sig, digest, hFunc)
+	return ok && err == nil, nil
+}
+
+// parsePublicKey decodes the compressed public key pubKey on inner.
+func parsePublicKey(inner twistededwards.ID, pubKey []byte) (signature.PublicKey, error) {
+	switch inner {
+	case twistededwards.BN254:
+		return decodePointWith(pubKey, func(a *edwardsbn254.PointAffine) signature.PublicKey { return &eddsabn254.PublicKey{A: *a} })
+	case twistededwards.BLS12_381:
+		return decodePointWith(pubKey, func(a *edwardsbls12381.PointAffine) signature.PublicKey { return &eddsabls12381.PublicKey{A: *a} })
+	case twistededwards.BLS12_377:
+		return decodePointWith(pubKey, func(a *edwardsbls12377.PointAffine) signature.PublicKey { return &eddsabls12377.PublicKey{A: *a} })
+	case twistededwards.BLS24_315:
+		return decodePointWith(pubKey, func(a *edwardsbls24315.PointAffine) signature.PublicKey { return &eddsabls24315.PublicKey{A: *a} })
+	default:
+		return nil, fmt.Errorf("unsupported twisted Edwards curve ID %d", inner)
+	}
+}
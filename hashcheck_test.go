@@ -0,0 +1,41 @@
+package main
+
+import (
+	"crypto/rand"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	cryptoeddsa "github.com/consensys/gnark-crypto/signature/eddsa"
+)
+
+func TestCheckSignatureHash(t *testing.T) {
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal("Error generating key:", err)
+	}
+	pubKey := privateKey.Public().Bytes()
+	msg := []byte("signed under mimc")
+	sig, err := SignMessageWith(HashMiMC, privateKey, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error signing message:", err)
+	}
+
+	if err := CheckSignatureHash(twistededwards.BN254, HashMiMC, pubKey, sig, msg, DefaultMessageLimbs); err != nil {
+		t.Fatal("Expected the signature to verify under its own hash:", err)
+	}
+
+	err = CheckSignatureHash(twistededwards.BN254, HashPoseidon2, pubKey, sig, msg, DefaultMessageLimbs)
+	if !errors.Is(err, ErrHashMismatch) || !errors.Is(err, ErrSignatureInvalid) {
+		t.Fatalf("Expected ErrHashMismatch for a Poseidon2 configuration, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "does under mimc") {
+		t.Fatalf("Expected the error to suggest mimc, got %q", err)
+	}
+
+	err = CheckSignatureHash(twistededwards.BN254, HashPoseidon2, pubKey, sig, []byte("another message"), DefaultMessageLimbs)
+	if !errors.Is(err, ErrSignatureInvalid) || errors.Is(err, ErrHashMismatch) {
+		t.Fatalf("Expected ErrSignatureInvalid without a suggestion for another message, got %v", err)
+	}
+}
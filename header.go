@@ -0,0 +1,133 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
+)
+
+// fileMagic opens every key and proof file written by SaveKeys and
+// SaveProof. It is followed by the curve ID as a big-endian uint16 and the
+// CircuitVersion of the constraint system the file belongs to.
+const fileMagic = "EDGNARK1"
+
+// fileHeaderSize is the size in bytes of the header of a key or proof file.
+const fileHeaderSize = len(fileMagic) + 2 + sha256.Size
+
+// CircuitVersion identifies a compiled constraint system: it is the SHA-256
+// of its serialization. Keys and proofs are only valid for the constraint
+// system they were produced for, and record its version to tell when they
+// are loaded for another one.
+type CircuitVersion [sha256.Size]byte
+
+// NewCircuitVersion returns the version of ccs.
+func NewCircuitVersion(ccs constraint.ConstraintSystem) (CircuitVersion, error) {
+	h := sha256.New()
+	if _, err := ccs.WriteTo(h); err != nil {
+		return CircuitVersion{}, fmt.Errorf("hashing constraint system: %w", err)
+	}
+	var v CircuitVersion
+	h.Sum(v[:0])
+	return v, nil
+}
+
+func (v CircuitVersion) String() string {
+	return hex.EncodeToString(v[:])
+}
+
+// fileHeader is the metadata written ahead of keys and proofs.
+type fileHeader struct {
+	curve   ecc.ID
+	circuit CircuitVersion
+}
+
+// newFileHeader returns the header of files produced for ccs.
+func newFileHeader(ccs constraint.ConstraintSystem) (fileHeader, error) {
+	curve, err := fieldCurve(ccs.Field())
+	if err != nil {
+		return fileHeader{}, err
+	}
+	v, err := NewCircuitVersion(ccs)
+	if err != nil {
+		return fileHeader{}, err
+	}
+	return fileHeader{curve: curve, circuit: v}, nil
+}
+
+// writeWithHeader writes h followed by src to path.
+func writeWithHeader(path string, h fileHeader, src io.WriterTo) error {
+	return writeToFile(path, headed{h.encode(), src})
+}
+
+// encode returns the encoding of h, fileHeaderSize bytes.
+func (h fileHeader) encode() []byte {
+	buf := make([]byte, 0, fileHeaderSize)
+	buf = append(buf, fileMagic...)
+	buf = binary.BigEndian.AppendUint16(buf, uint16(h.curve))
+	buf = append(buf, h.circuit[:]...)
+	return buf
+}
+
+// readWithHeader reads the header of the file at path, checks that it was
+// written for curve and, if circuit is not nil, for that circuit version,
+// then decodes the rest of the file into dst.
+func readWithHeader(path string, curve ecc.ID, circuit *CircuitVersion, dst io.ReaderFrom) (fileHeader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return fileHeader{}, err
+	}
+	defer f.Close()
+
+	h, err := readHeader(path, f)
+	if err != nil {
+		return fileHeader{}, err
+	}
+	if h.curve != curve {
+		return fileHeader{}, fmt.Errorf("%s was written for %s, not %s", path, h.curve, curve)
+	}
+	if circuit != nil && h.circuit != *circuit {
+		return fileHeader{}, fmt.Errorf("%s was produced for circuit version %s, not %s", path, h.circuit, circuit)
+	}
+	return h, readFrom(path, f, dst)
+}
+
+func readHeader(name string, r io.Reader) (fileHeader, error) {
+	buf := make([]byte, fileHeaderSize)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return fileHeader{}, fmt.Errorf("%s is too short to hold a header", name)
+		}
+		return fileHeader{}, err
+	}
+	if string(buf[:len(fileMagic)]) != fileMagic {
+		return fileHeader{}, fmt.Errorf("%s is not a key or proof file, or was written by an incompatible version", name)
+	}
+	buf = buf[len(fileMagic):]
+
+	var h fileHeader
+	h.curve = ecc.ID(binary.BigEndian.Uint16(buf))
+	copy(h.circuit[:], buf[2:])
+	return h, nil
+}
+
+// headed is an io.WriterTo writing a fixed header before src.
+type headed struct {
+	header []byte
+	src    io.WriterTo
+}
+
+func (h headed) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(h.header)
+	if err != nil {
+		return int64(n), err
+	}
+	m, err := h.src.WriteTo(w)
+	return int64(n) + m, err
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+)
+
+func TestFileHeader(t *testing.T) {
+	ccs, err := CompileCircuit(ecc.BN254, backend.GROTH16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, vk, err := SetupGroth16(ccs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := SaveKeys(ccs, pk, vk, dir); err != nil {
+		t.Fatal("Error saving keys:", err)
+	}
+
+	// Compiling the same circuit again yields the same version
+	recompiled, err := CompileCircuit(ecc.BN254, backend.GROTH16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := LoadKeys(dir, recompiled); err != nil {
+		t.Fatal("Error loading keys for the recompiled circuit:", err)
+	}
+
+	// Keys are rejected for a circuit with another number of message limbs
+	other, err := compileCircuit(ecc.BN254, backend.GROTH16, NewEdDSACircuit(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := LoadKeys(dir, other); err == nil || !strings.Contains(err.Error(), "circuit version") {
+		t.Fatal("Expected a circuit version mismatch, got:", err)
+	}
+
+	// Corrupting the magic or the curve of the header is reported
+	vkPath := filepath.Join(dir, verifyingKeyFile)
+	original, err := os.ReadFile(vkPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, offset := range map[string]int{"magic": 0, "curve": len(fileMagic) + 1} {
+		corrupted := append([]byte(nil), original...)
+		corrupted[offset] ^= 0xff
+		if err := os.WriteFile(vkPath, corrupted, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := LoadKeys(dir, ccs); err == nil {
+			t.Fatalf("Expected an error loading keys with a corrupted %s", name)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	tedwards "github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/signature/eddsa"
+)
+
+// HiddenKeyEdDSACircuit proves that a signature of Message verifies under a
+// BN254 public key without revealing the key, only KeyCommitment, the MiMC
+// hash of its affine coordinates as computed by PublicKeyLeaf. The signature
+// is secret too, since checking it against a candidate key would tell
+// whether that key signed.
+//
+// The commitment is not blinded: anyone holding a candidate public key can
+// hash it and compare. It hides the signer among the keys a verifier does
+// not know, and binds the proof to one key, for instance one the verifier
+// registered earlier. To hide the signer among a known set of keys, use
+// MembershipEdDSACircuit, whose Merkle leaves are these commitments.
+type HiddenKeyEdDSACircuit struct {
+	KeyCommitment frontend.Variable   `gnark:",public"`
+	Message       []frontend.Variable `gnark:",public"`
+
+	PublicKey eddsa.PublicKey
+	Signature eddsa.Signature
+}
+
+// NewHiddenKeyCircuit returns a hidden key circuit for messages of nbLimbs
+// limbs.
+func NewHiddenKeyCircuit(nbLimbs int) *HiddenKeyEdDSACircuit {
+	return &HiddenKeyEdDSACircuit{Message: make([]frontend.Variable, nbLimbs)}
+}
+
+// NewHiddenKeyAssignment returns an assignment of the hidden key circuit for
+// a signature produced by SignMessage, with DefaultMessageLimbs message
+// limbs and the commitment PublicKeyLeaf(pubKey).
+func NewHiddenKeyAssignment(pubKey, sig, msg []byte) (*HiddenKeyEdDSACircuit, error) {
+	commitment, err := PublicKeyLeaf(pubKey)
+	if err != nil {
+		return nil, err
+	}
+	limbs, err := MessageLimbs(msg, DefaultMessageLimbs)
+	if err != nil {
+		return nil, err
+	}
+
+	assignment := &HiddenKeyEdDSACircuit{KeyCommitment: commitment, Message: limbs}
+	if err := assignEncodings(twistededwards.BN254, &assignment.PublicKey, &assignment.Signature, pubKey, sig); err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
+
+// Define implements the circuit for EdDSA signature verification under a
+// committed public key
+func (circuit *HiddenKeyEdDSACircuit) Define(api frontend.API) error {
+	curve, err := tedwards.NewEdCurve(api, twistededwards.BN254)
+	if err != nil {
+		return err
+	}
+	hash, err := newMiMCFieldHasher(api)
+	if err != nil {
+		return err
+	}
+
+	// The secret public key must be the committed one
+	hash.Write(circuit.PublicKey.A.X, circuit.PublicKey.A.Y)
+	api.AssertIsEqual(circuit.KeyCommitment, hash.Sum())
+	hash.Reset()
+
+	return verifyMessageSignature(curve, hash, circuit.PublicKey, circuit.Signature, circuit.Message)
+}
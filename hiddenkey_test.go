@@ -0,0 +1,28 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/test"
+)
+
+func TestHiddenKeyEdDSACircuit(t *testing.T) {
+	pubKeys, sigs, msgs := signBatch(t, 2)
+	assignment, err := NewHiddenKeyAssignment(pubKeys[0], sigs[0], msgs[0])
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+
+	assert := test.NewAssert(t)
+	assert.SolvingSucceeded(NewHiddenKeyCircuit(DefaultMessageLimbs), assignment, test.WithCurves(ecc.BN254))
+
+	// The commitment of another key does not match the secret key
+	other, err := PublicKeyLeaf(pubKeys[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	assignment.KeyCommitment = other
+	assert.SolvingFailed(NewHiddenKeyCircuit(DefaultMessageLimbs), assignment, test.WithCurves(ecc.BN254))
+
+}
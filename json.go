@@ -0,0 +1,161 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+
+	frbls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	frbls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	frbls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	frbn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	frbw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/backend/witness"
+)
+
+// AssignmentJSON is the JSON form of the inputs of an EdDSACircuit
+// assignment. Each field is hex encoded, with or without a 0x prefix.
+type AssignmentJSON struct {
+	Message   string `json:"message"`
+	PublicKey string `json:"publicKey"`
+	Signature string `json:"signature"`
+}
+
+// AssignmentFromJSON reads an AssignmentJSON object from r and builds the
+// corresponding EdDSACircuit assignment with DefaultMessageLimbs limbs.
+// Missing or malformed fields are reported by name.
+func AssignmentFromJSON(r io.Reader) (*EdDSACircuit, error) {
+	var in AssignmentJSON
+	if err := json.NewDecoder(r).Decode(&in); err != nil {
+		return nil, fmt.Errorf("decoding assignment: %w", err)
+	}
+	pubKey, sig, msg, err := in.decode()
+	if err != nil {
+		return nil, err
+	}
+	return NewAssignment(pubKey, sig, msg, DefaultMessageLimbs)
+}
+
+// WriteAssignmentJSON writes the inputs of an assignment as an
+// AssignmentJSON object to w. It is the inverse of AssignmentFromJSON.
+func WriteAssignmentJSON(w io.Writer, pubKey, sig, msg []byte) error {
+	out := AssignmentJSON{
+		Message:   "0x" + hex.EncodeToString(msg),
+		PublicKey: "0x" + hex.EncodeToString(pubKey),
+		Signature: "0x" + hex.EncodeToString(sig),
+	}
+	if err := json.NewEncoder(w).Encode(out); err != nil {
+		return fmt.Errorf("encoding assignment: %w", err)
+	}
+	return nil
+}
+
+// decode returns the raw public key, signature and message bytes.
+func (in AssignmentJSON) decode() (pubKey, sig, msg []byte, err error) {
+	if msg, err = decodeHexField("message", in.Message); err != nil {
+		return nil, nil, nil, err
+	}
+	if pubKey, err = decodeHexField("publicKey", in.PublicKey); err != nil {
+		return nil, nil, nil, err
+	}
+	if err := ValidatePublicKey(twistededwards.BN254, pubKey); err != nil {
+		return nil, nil, nil, fmt.Errorf("publicKey: %w", err)
+	}
+	if sig, err = decodeHexField("signature", in.Signature); err != nil {
+		return nil, nil, nil, err
+	}
+	return pubKey, sig, msg, nil
+}
+
+func decodeHexField(name, value string) ([]byte, error) {
+	if value == "" {
+		return nil, fmt.Errorf("%s: missing", name)
+	}
+	b, err := decodeHex(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return b, nil
+}
+
+// decodeHex decodes a hex string, with or without a 0x prefix.
+func decodeHex(s string) ([]byte, error) {
+	if len(s) >= 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') {
+		s = s[2:]
+	}
+	return hex.DecodeString(s)
+}
+
+// WitnessDump is the JSON form of a witness written by DumpWitness. Elements
+// holds the public values followed by the secret ones, in the order of the
+// fields of the circuit, each as a 0x-prefixed hex field element.
+type WitnessDump struct {
+	NbPublic int      `json:"nbPublic"`
+	NbSecret int      `json:"nbSecret"`
+	Elements []string `json:"elements"`
+}
+
+// DumpWitness returns w as an indented WitnessDump JSON object, to compare
+// the values a prover and a verifier assigned when a proof fails to verify.
+func DumpWitness(w witness.Witness) (string, error) {
+	elements, err := witnessElements(w)
+	if err != nil {
+		return "", err
+	}
+	public, err := w.Public()
+	if err != nil {
+		return "", fmt.Errorf("extracting public witness: %w", err)
+	}
+	publicElements, err := witnessElements(public)
+	if err != nil {
+		return "", err
+	}
+
+	dump := WitnessDump{
+		NbPublic: len(publicElements),
+		NbSecret: len(elements) - len(publicElements),
+		Elements: make([]string, len(elements)),
+	}
+	for i := range elements {
+		dump.Elements[i] = fmt.Sprintf("%#x", elements[i])
+	}
+	out, err := json.MarshalIndent(dump, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encoding witness: %w", err)
+	}
+	return string(out), nil
+}
+
+// witnessElements returns the values of w as integers.
+func witnessElements(w witness.Witness) ([]*big.Int, error) {
+	switch v := w.Vector().(type) {
+	case frbn254.Vector:
+		return vectorElements(v), nil
+	case frbls12381.Vector:
+		return vectorElements(v), nil
+	case frbls12377.Vector:
+		return vectorElements(v), nil
+	case frbls24315.Vector:
+		return vectorElements(v), nil
+	case frbw6761.Vector:
+		return vectorElements(v), nil
+	default:
+		return nil, fmt.Errorf("unsupported witness vector %T", v)
+	}
+}
+
+// vectorElements returns the field elements of a gnark-crypto fr vector as
+// integers.
+func vectorElements[E any, P interface {
+	*E
+	BigInt(res *big.Int) *big.Int
+}](v []E) []*big.Int {
+	elements := make([]*big.Int, len(v))
+	for i := range v {
+		elements[i] = P(&v[i]).BigInt(new(big.Int))
+	}
+	return elements
+}
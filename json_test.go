@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	cryptoeddsa "github.com/consensys/gnark-crypto/signature/eddsa"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+func TestAssignmentJSONRoundTrip(t *testing.T) {
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte{0xde, 0xad, 0xf0, 0x0d}
+	sig, err := SignMessage(privateKey, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteAssignmentJSON(&buf, privateKey.Public().Bytes(), sig, msg); err != nil {
+		t.Fatal("Error writing assignment:", err)
+	}
+	assignment, err := AssignmentFromJSON(&buf)
+	if err != nil {
+		t.Fatal("Error reading assignment:", err)
+	}
+
+	assert := test.NewAssert(t)
+	assert.SolvingSucceeded(NewEdDSACircuit(DefaultMessageLimbs), assignment, test.WithCurves(ecc.BN254))
+}
+
+func TestAssignmentFromJSONErrors(t *testing.T) {
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKeyHex := "0x" + hex.EncodeToString(privateKey.Public().Bytes())
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"not json", `{`, "decoding assignment"},
+		{"missing message", `{"publicKey":"` + pubKeyHex + `","signature":"0x00"}`, "message: missing"},
+		{"bad message hex", `{"message":"0xzz","publicKey":"` + pubKeyHex + `","signature":"0x00"}`, "message: encoding/hex"},
+		{"missing public key", `{"message":"0x01","signature":"0x00"}`, "publicKey: missing"},
+		{"bad public key", `{"message":"0x01","publicKey":"0x0102","signature":"0x00"}`, "publicKey: public key must be 32 bytes"},
+		{"missing signature", `{"message":"0x01","publicKey":"` + pubKeyHex + `"}`, "signature: missing"},
+		{"bad signature hex", `{"message":"0x01","publicKey":"` + pubKeyHex + `","signature":"0x0"}`, "signature: encoding/hex"},
+		{"short signature", `{"message":"0x01","publicKey":"` + pubKeyHex + `","signature":"0x00"}`, "signature must be 64 bytes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := AssignmentFromJSON(strings.NewReader(tt.input))
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Expected error containing %q, got %q", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestDumpWitness(t *testing.T) {
+	valid, _ := newTestAssignments(t)
+	fullWitness, err := frontend.NewWitness(valid, ecc.BN254.ScalarField())
+	if err != nil {
+		t.Fatal(err)
+	}
+	dump, err := DumpWitness(fullWitness)
+	if err != nil {
+		t.Fatal("Error dumping witness:", err)
+	}
+
+	var got WitnessDump
+	if err := json.Unmarshal([]byte(dump), &got); err != nil {
+		t.Fatal("Expected valid JSON:", err)
+	}
+	// The public key and signature coordinates and the message limbs, with no
+	// secret values
+	nbPublic := 5 + DefaultMessageLimbs
+	if got.NbPublic != nbPublic || got.NbSecret != 0 || len(got.Elements) != nbPublic {
+		t.Fatalf("Expected %d public elements, got %d public, %d secret and %d elements", nbPublic, got.NbPublic, got.NbSecret, len(got.Elements))
+	}
+	x, ok := new(big.Int).SetString(got.Elements[0], 0)
+	if !ok || x.Cmp(new(big.Int).SetBytes(valid.PublicKey.A.X.([]byte))) != 0 {
+		t.Fatalf("Expected the public key X first, got %s", got.Elements[0])
+	}
+
+	// A circuit with secret values lists them after the public ones
+	pubKeys, sigs, msgs := signBatch(t, 1)
+	hidden, err := NewHiddenKeyAssignment(pubKeys[0], sigs[0], msgs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	fullWitness, err = frontend.NewWitness(hidden, ecc.BN254.ScalarField())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dump, err = DumpWitness(fullWitness); err != nil {
+		t.Fatal("Error dumping witness:", err)
+	}
+	if err := json.Unmarshal([]byte(dump), &got); err != nil {
+		t.Fatal("Expected valid JSON:", err)
+	}
+	if got.NbPublic != 1+DefaultMessageLimbs || got.NbSecret != 5 || len(got.Elements) != 6+DefaultMessageLimbs {
+		t.Fatalf("Unexpected counts: %d public, %d secret and %d elements", got.NbPublic, got.NbSecret, len(got.Elements))
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/backend/groth16"
+)
+
+// Verify checks a BN254 Groth16 proof of the EdDSA circuit from string
+// inputs, as passed from JavaScript: the public key, signature and message
+// as hex, and the proof and verifying key as base64 of their WriteTo
+// encoding. It returns "ok" if the proof verifies and the error message
+// otherwise. wasm.go exposes it to the browser.
+func Verify(pubKeyHex, sigHex, msgHex, proofB64, vkB64 string) string {
+	if err := verifyEncoded(pubKeyHex, sigHex, msgHex, proofB64, vkB64); err != nil {
+		return err.Error()
+	}
+	return "ok"
+}
+
+func verifyEncoded(pubKeyHex, sigHex, msgHex, proofB64, vkB64 string) error {
+	pubKey, err := decodeHex(pubKeyHex)
+	if err != nil {
+		return fmt.Errorf("public key: %w", err)
+	}
+	sig, err := decodeHex(sigHex)
+	if err != nil {
+		return fmt.Errorf("signature: %w", err)
+	}
+	msg, err := decodeHex(msgHex)
+	if err != nil {
+		return fmt.Errorf("message: %w", err)
+	}
+
+	proof := groth16.NewProof(ecc.BN254)
+	if err := readBase64("proof", proofB64, proof); err != nil {
+		return err
+	}
+	vk := groth16.NewVerifyingKey(ecc.BN254)
+	if err := readBase64("verifying key", vkB64, vk); err != nil {
+		return err
+	}
+
+	if err := ValidatePublicKey(twistededwards.BN254, pubKey); err != nil {
+		return fmt.Errorf("invalid public key: %w", err)
+	}
+	return VerifyProof(vk, proof, pubKey, sig, msg)
+}
+
+func readBase64(name, s string, dst io.ReaderFrom) error {
+	buf, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return readFrom(name, bytes.NewReader(buf), dst)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"testing"
+)
+
+func encodeBase64(tb testing.TB, src io.WriterTo) string {
+	tb.Helper()
+	var buf bytes.Buffer
+	if _, err := src.WriteTo(&buf); err != nil {
+		tb.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func TestVerifyEncoded(t *testing.T) {
+	vk, jobs := proveBatch(t, 1)
+	job := jobs[0]
+	proofB64, vkB64 := encodeBase64(t, job.Proof), encodeBase64(t, vk)
+	pubKeyHex, msgHex := hex.EncodeToString(job.PublicKey), hex.EncodeToString(job.Message)
+
+	if got := Verify(pubKeyHex, hex.EncodeToString(job.Signature), msgHex, proofB64, vkB64); got != "ok" {
+		t.Fatal("Error verifying valid proof:", got)
+	}
+
+	tampered := bytes.Clone(job.Signature)
+	tampered[len(tampered)-1] ^= 0x01
+	if got := Verify(pubKeyHex, hex.EncodeToString(tampered), msgHex, proofB64, vkB64); got == "ok" {
+		t.Fatal("Expected a tampered signature to be reported")
+	}
+}
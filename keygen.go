@@ -0,0 +1,245 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+
+	eddsabls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/twistededwards/eddsa"
+	eddsabls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/twistededwards/eddsa"
+	eddsabls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/twistededwards/eddsa"
+	eddsabn254 "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards/eddsa"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark-crypto/signature"
+	cryptoeddsa "github.com/consensys/gnark-crypto/signature/eddsa"
+)
+
+// NewKeyFromSeed derives an EdDSA key pair on inner deterministically from
+// seed: the same seed always yields the same key.
+//
+// This is meant for reproducible tests, demos and golden vectors only. Keys
+// are exactly as secret as their seed, so it must never be used to generate
+// production keys.
+func NewKeyFromSeed(inner twistededwards.ID, seed []byte) (signature.Signer, error) {
+	if len(seed) == 0 {
+		return nil, errors.New("seed must not be empty")
+	}
+	if inner == bandersnatch.inner {
+		return nil, ErrBandersnatchEdDSA
+	}
+	return cryptoeddsa.New(inner, newSeedReader(seed))
+}
+
+// GenerateKeys generates n EdDSA key pairs on inner with one worker per CPU,
+// for load tests that need many signers. Every key draws from
+// crypto/rand.Reader, which is safe for concurrent use, so the workers share
+// no other state and no randomness is reused between keys.
+func GenerateKeys(n int, inner twistededwards.ID) ([]signature.Signer, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("key count must not be negative, got %d", n)
+	}
+	if _, err := outerCurve(inner); err != nil {
+		return nil, err
+	}
+	keys := make([]signature.Signer, n)
+	errs := make([]error, n)
+
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < max(1, min(runtime.NumCPU(), n)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				keys[i], errs[i] = cryptoeddsa.New(inner, rand.Reader)
+			}
+		}()
+	}
+	for i := range keys {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("generating keys: %w", err)
+		}
+	}
+	return keys, nil
+}
+
+// PublicKeyBytes returns the compressed public key of priv, the encoding
+// NewAssignment and eddsa.PublicKey.Assign expect.
+func PublicKeyBytes(priv signature.Signer) []byte {
+	return priv.Public().Bytes()
+}
+
+// WriteKeyPair writes the private key bytes of priv to privPath, readable by
+// its owner only, and its public key bytes to pubPath. With hexEncoding, both
+// files hold a hex line instead of the raw bytes. readKeyFile reads either.
+func WriteKeyPair(priv signature.Signer, privPath, pubPath string, hexEncoding bool) error {
+	encode := func(b []byte) []byte { return b }
+	if hexEncoding {
+		encode = func(b []byte) []byte { return []byte(hex.EncodeToString(b) + "\n") }
+	}
+	if err := os.WriteFile(privPath, encode(priv.Bytes()), 0o600); err != nil {
+		return fmt.Errorf("saving private key: %w", err)
+	}
+	if err := os.WriteFile(pubPath, encode(PublicKeyBytes(priv)), 0o644); err != nil {
+		return fmt.Errorf("saving public key: %w", err)
+	}
+	return nil
+}
+
+// privateKeyMagic opens every private key file written by SavePrivateKey.
+// It is followed by the twisted Edwards curve ID of the key as a big-endian
+// uint16, the length of the key bytes as a big-endian uint16, and the key
+// bytes, as returned by its Bytes method.
+const privateKeyMagic = "EDGNKEY1"
+
+// privateKeyHeaderSize is the size in bytes of the header of a private key
+// file.
+const privateKeyHeaderSize = len(privateKeyMagic) + 2 + 2
+
+// SavePrivateKey writes priv, an EdDSA private key made by the gnark-crypto
+// eddsa package, to path, readable by its owner only. The file records the
+// curve of the key, so that LoadPrivateKey rejects it for another curve.
+func SavePrivateKey(priv signature.Signer, path string) error {
+	inner, err := signerCurve(priv)
+	if err != nil {
+		return err
+	}
+	key := priv.Bytes()
+	buf := make([]byte, 0, privateKeyHeaderSize+len(key))
+	buf = append(buf, privateKeyMagic...)
+	buf = binary.BigEndian.AppendUint16(buf, uint16(inner))
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(key)))
+	buf = append(buf, key...)
+	if err := os.WriteFile(path, buf, 0o600); err != nil {
+		return fmt.Errorf("saving private key: %w", err)
+	}
+	return nil
+}
+
+// LoadPrivateKey reads a private key on inner written by SavePrivateKey from
+// path. Files that are truncated, hold a key on another curve or are not
+// private key files are reported as such.
+func LoadPrivateKey(inner twistededwards.ID, path string) (signature.Signer, error) {
+	if _, err := outerCurve(inner); err != nil {
+		return nil, err
+	}
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("loading private key: %w", err)
+	}
+	if len(buf) < privateKeyHeaderSize {
+		return nil, fmt.Errorf("%s is too short to hold a private key header", path)
+	}
+	if string(buf[:len(privateKeyMagic)]) != privateKeyMagic {
+		return nil, fmt.Errorf("%s is not a private key file", path)
+	}
+	buf = buf[len(privateKeyMagic):]
+	if curve := twistededwards.ID(binary.BigEndian.Uint16(buf)); curve != inner {
+		return nil, fmt.Errorf("%s holds a %s key, not %s", path, innerCurveName(curve), innerCurveName(inner))
+	}
+	size := int(binary.BigEndian.Uint16(buf[2:]))
+	buf = buf[4:]
+	if len(buf) != size {
+		return nil, fmt.Errorf("%s holds %d bytes of a %d-byte private key", path, len(buf), size)
+	}
+
+	privateKey, err := cryptoeddsa.New(inner, rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	if len(privateKey.Bytes()) != size {
+		return nil, fmt.Errorf("%s holds a %d-byte private key, %s keys are %d bytes", path, size, innerCurveName(inner), len(privateKey.Bytes()))
+	}
+	if _, err := privateKey.SetBytes(buf); err != nil {
+		return nil, fmt.Errorf("%s does not hold a valid %s private key: %w", path, innerCurveName(inner), err)
+	}
+	return privateKey, nil
+}
+
+// signerCurve returns the twisted Edwards curve of priv.
+func signerCurve(priv signature.Signer) (twistededwards.ID, error) {
+	switch priv.(type) {
+	case *eddsabn254.PrivateKey:
+		return twistededwards.BN254, nil
+	case *eddsabls12381.PrivateKey:
+		return twistededwards.BLS12_381, nil
+	case *eddsabls12377.PrivateKey:
+		return twistededwards.BLS12_377, nil
+	case *eddsabls24315.PrivateKey:
+		return twistededwards.BLS24_315, nil
+	default:
+		return twistededwards.UNKNOWN, fmt.Errorf("unsupported private key type %T", priv)
+	}
+}
+
+// readKeyFile reads a key file written by WriteKeyPair, decoding it if it
+// holds a hex line and returning the raw bytes otherwise.
+func readKeyFile(path string) ([]byte, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if decoded, err := hex.DecodeString(string(bytes.TrimSpace(buf))); err == nil {
+		return decoded, nil
+	}
+	return buf, nil
+}
+
+// loadPrivateKeyFile reads a BN254 EdDSA private key stored, raw or as hex,
+// as the bytes returned by its Bytes method.
+func loadPrivateKeyFile(path string) (signature.Signer, error) {
+	buf, err := readKeyFile(path)
+	if err != nil {
+		return nil, err
+	}
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := privateKey.SetBytes(buf); err != nil {
+		return nil, fmt.Errorf("%s does not hold a BN254 EdDSA private key: %w", path, err)
+	}
+	return privateKey, nil
+}
+
+// seedReader is a deterministic byte stream expanding a seed with SHA-256 in
+// counter mode: block i is SHA-256(seed || i) with i as a big-endian uint64.
+type seedReader struct {
+	seed    []byte
+	counter uint64
+	buf     []byte
+}
+
+func newSeedReader(seed []byte) *seedReader {
+	return &seedReader{seed: append([]byte(nil), seed...)}
+}
+
+func (r *seedReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(r.buf) == 0 {
+			var counter [8]byte
+			binary.BigEndian.PutUint64(counter[:], r.counter)
+			r.counter++
+			block := sha256.Sum256(append(r.seed, counter[:]...))
+			r.buf = block[:]
+		}
+		c := copy(p[n:], r.buf)
+		r.buf = r.buf[c:]
+		n += c
+	}
+	return n, nil
+}
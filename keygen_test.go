@@ -0,0 +1,207 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	cryptoeddsa "github.com/consensys/gnark-crypto/signature/eddsa"
+	"github.com/consensys/gnark/std/signature/eddsa"
+)
+
+func TestGenerateKeys(t *testing.T) {
+	const n = 64
+	keys, err := GenerateKeys(n, twistededwards.BN254)
+	if err != nil {
+		t.Fatal("Error generating keys:", err)
+	}
+	if len(keys) != n {
+		t.Fatalf("Expected %d keys, got %d", n, len(keys))
+	}
+
+	msg := []byte{0xde, 0xad, 0xf0, 0x0d}
+	digest, err := HashMessage(msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[string]bool)
+	for i, key := range keys {
+		pubKey := string(PublicKeyBytes(key))
+		if seen[pubKey] {
+			t.Fatalf("Key %d duplicates an earlier key", i)
+		}
+		seen[pubKey] = true
+
+		sig, err := key.Sign(digest, mimc.NewMiMC())
+		if err != nil {
+			t.Fatalf("Error signing with key %d: %v", i, err)
+		}
+		if ok, err := key.Public().Verify(sig, digest, mimc.NewMiMC()); err != nil || !ok {
+			t.Fatalf("Signature of key %d does not verify: %v", i, err)
+		}
+	}
+
+	if _, err := GenerateKeys(1, twistededwards.BLS12_381_BANDERSNATCH); err == nil {
+		t.Fatal("Expected an error generating Bandersnatch keys")
+	}
+}
+
+func TestPublicKeyBytes(t *testing.T) {
+	privateKey, err := NewKeyFromSeed(twistededwards.BN254, []byte("eddsa-gnark test seed"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey := PublicKeyBytes(privateKey)
+
+	// Assign decompresses the key into its coordinates, which compress back
+	// to the same bytes
+	var assigned eddsa.PublicKey
+	assigned.Assign(twistededwards.BN254, pubKey)
+	x, okX := assigned.A.X.([]byte)
+	y, okY := assigned.A.Y.([]byte)
+	if !okX || !okY {
+		t.Fatalf("Expected Assign to set byte coordinates, got %T and %T", assigned.A.X, assigned.A.Y)
+	}
+	compressed, err := compressPoint(twistededwards.BN254, x, y)
+	if err != nil {
+		t.Fatal("Error compressing assigned public key:", err)
+	}
+	if !bytes.Equal(compressed, pubKey) {
+		t.Fatal("Expected the assigned public key to compress back to PublicKeyBytes")
+	}
+}
+
+func TestNewKeyFromSeed(t *testing.T) {
+	seed := []byte("eddsa-gnark test seed")
+
+	first, err := NewKeyFromSeed(twistededwards.BN254, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := NewKeyFromSeed(twistededwards.BN254, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(first.Bytes(), second.Bytes()) {
+		t.Fatal("Expected the same seed to yield the same private key")
+	}
+	if !bytes.Equal(first.Public().Bytes(), second.Public().Bytes()) {
+		t.Fatal("Expected the same seed to yield the same public key")
+	}
+
+	other, err := NewKeyFromSeed(twistededwards.BN254, []byte("another seed"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(first.Public().Bytes(), other.Public().Bytes()) {
+		t.Fatal("Expected different seeds to yield different keys")
+	}
+}
+
+func TestNewKeyFromSeedEmpty(t *testing.T) {
+	if _, err := NewKeyFromSeed(twistededwards.BN254, nil); err == nil {
+		t.Fatal("Expected an error for an empty seed")
+	}
+}
+
+func TestWriteKeyPair(t *testing.T) {
+	privateKey, err := NewKeyFromSeed(twistededwards.BN254, []byte("eddsa-gnark test seed"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, hexEncoding := range []bool{false, true} {
+		dir := t.TempDir()
+		privPath, pubPath := filepath.Join(dir, "eddsa.key"), filepath.Join(dir, "eddsa.pub")
+		if err := WriteKeyPair(privateKey, privPath, pubPath, hexEncoding); err != nil {
+			t.Fatal("Error writing keys:", err)
+		}
+
+		loaded, err := loadPrivateKeyFile(privPath)
+		if err != nil {
+			t.Fatal("Error loading private key:", err)
+		}
+		pubKey, err := readKeyFile(pubPath)
+		if err != nil {
+			t.Fatal("Error loading public key:", err)
+		}
+		if !bytes.Equal(pubKey, privateKey.Public().Bytes()) {
+			t.Fatal("Expected the public key file to hold the public key")
+		}
+
+		// The loaded key must sign messages that the written public key
+		// verifies
+		msg := []byte{0xde, 0xad, 0xf0, 0x0d}
+		sig, err := SignMessage(loaded, msg, DefaultMessageLimbs)
+		if err != nil {
+			t.Fatal("Error signing message:", err)
+		}
+		digest, err := HashMessage(msg, DefaultMessageLimbs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		isValid, err := loaded.Public().Verify(sig, digest, mimc.NewMiMC())
+		if err != nil || !isValid {
+			t.Fatal("Expected the signature to verify:", err)
+		}
+		if !bytes.Equal(loaded.Public().Bytes(), pubKey) {
+			t.Fatal("Expected the loaded private key to match the public key file")
+		}
+	}
+}
+
+func TestSavePrivateKey(t *testing.T) {
+	dir := t.TempDir()
+	for _, inner := range []twistededwards.ID{twistededwards.BN254, twistededwards.BLS12_381} {
+		privateKey, err := cryptoeddsa.New(inner, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, innerCurveName(inner)+".key")
+		if err := SavePrivateKey(privateKey, path); err != nil {
+			t.Fatal("Error saving private key:", err)
+		}
+		loaded, err := LoadPrivateKey(inner, path)
+		if err != nil {
+			t.Fatal("Error loading private key:", err)
+		}
+		if !bytes.Equal(loaded.Bytes(), privateKey.Bytes()) {
+			t.Fatalf("Loaded %s private key differs from the saved one", innerCurveName(inner))
+		}
+	}
+
+	bn254Path := filepath.Join(dir, "BN254.key")
+	const wantCurve = "holds a BN254 key, not BLS12-381"
+	if _, err := LoadPrivateKey(twistededwards.BLS12_381, bn254Path); err == nil || !strings.Contains(err.Error(), wantCurve) {
+		t.Fatalf("Expected an error containing %q, got %v", wantCurve, err)
+	}
+
+	buf, err := os.ReadFile(bn254Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		name    string
+		content []byte
+		wantErr string
+	}{
+		{"truncated key", buf[:len(buf)-1], "holds 95 bytes of a 96-byte private key"},
+		{"truncated header", buf[:privateKeyHeaderSize-1], "too short"},
+		{"raw key", buf[privateKeyHeaderSize:], "not a private key file"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, "bad.key")
+			if err := os.WriteFile(path, tt.content, 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadPrivateKey(twistededwards.BN254, path); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Expected an error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/consensys/gnark"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/constraint"
+	gnarkio "github.com/consensys/gnark/io"
+)
+
+const (
+	provingKeyFile   = "groth16.pk"
+	verifyingKeyFile = "groth16.vk"
+)
+
+// Encoding selects how SaveKeysWith and SaveProofWith write curve points.
+// Files in either encoding are read back by LoadKeys and LoadProof.
+type Encoding int
+
+const (
+	// Compressed writes each point as one coordinate and a sign bit. Files
+	// are about half the size, but loading them takes a square root per
+	// point.
+	Compressed Encoding = iota
+	// Uncompressed writes both coordinates of each point, with the RawBytes
+	// encoding of gnark-crypto, for larger files that load faster.
+	Uncompressed
+)
+
+func (e Encoding) String() string {
+	switch e {
+	case Compressed:
+		return "compressed"
+	case Uncompressed:
+		return "uncompressed"
+	default:
+		return fmt.Sprintf("Encoding(%d)", int(e))
+	}
+}
+
+// encode returns src as an io.WriterTo writing its points with e.
+func (e Encoding) encode(src interface {
+	io.WriterTo
+	gnarkio.WriterRawTo
+}) (io.WriterTo, error) {
+	switch e {
+	case Compressed:
+		return src, nil
+	case Uncompressed:
+		return rawWriter{src}, nil
+	default:
+		return nil, fmt.Errorf("unsupported encoding %s", e)
+	}
+}
+
+// rawWriter writes with WriteRawTo in place of WriteTo.
+type rawWriter struct {
+	src gnarkio.WriterRawTo
+}
+
+func (r rawWriter) WriteTo(w io.Writer) (int64, error) {
+	return r.src.WriteRawTo(w)
+}
+
+// SaveKeys writes the Groth16 proving and verifying keys set up for ccs to
+// dir, creating the directory if needed. Each file starts with a header
+// recording the curve and the CircuitVersion of ccs. Points are compressed;
+// see SaveKeysWith.
+func SaveKeys(ccs constraint.ConstraintSystem, pk groth16.ProvingKey, vk groth16.VerifyingKey, dir string) error {
+	return SaveKeysWith(ccs, pk, vk, dir, Compressed)
+}
+
+// SaveKeysWith is SaveKeys writing points with the encoding enc.
+func SaveKeysWith(ccs constraint.ConstraintSystem, pk groth16.ProvingKey, vk groth16.VerifyingKey, dir string, enc Encoding) error {
+	pkSrc, err := enc.encode(pk)
+	if err != nil {
+		return err
+	}
+	vkSrc, err := enc.encode(vk)
+	if err != nil {
+		return err
+	}
+	h, err := newFileHeader(ccs)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating key directory: %w", err)
+	}
+	if err := writeWithHeader(filepath.Join(dir, provingKeyFile), h, pkSrc); err != nil {
+		return fmt.Errorf("saving proving key: %w", err)
+	}
+	if err := writeWithHeader(filepath.Join(dir, verifyingKeyFile), h, vkSrc); err != nil {
+		return fmt.Errorf("saving verifying key: %w", err)
+	}
+	return nil
+}
+
+// LoadKeys reads the Groth16 proving and verifying keys written by SaveKeys
+// or SaveKeysWith from dir, for use with ccs. Keys whose header records
+// another curve or circuit version than ccs, or that were produced by an
+// incompatible gnark version, yield an error rather than a panic.
+func LoadKeys(dir string, ccs constraint.ConstraintSystem) (groth16.ProvingKey, groth16.VerifyingKey, error) {
+	h, err := newFileHeader(ccs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pk := groth16.NewProvingKey(h.curve)
+	if _, err := readWithHeader(filepath.Join(dir, provingKeyFile), h.curve, &h.circuit, pk); err != nil {
+		return nil, nil, fmt.Errorf("loading proving key: %w", err)
+	}
+	vk := groth16.NewVerifyingKey(h.curve)
+	if _, err := readWithHeader(filepath.Join(dir, verifyingKeyFile), h.curve, &h.circuit, vk); err != nil {
+		return nil, nil, fmt.Errorf("loading verifying key: %w", err)
+	}
+	return pk, vk, nil
+}
+
+// LoadOrSetupKeys returns the Groth16 keys for ccs saved in dir, running
+// SetupGroth16 and saving its keys there first if dir holds none.
+//
+// groth16.Setup draws its toxic waste from crypto/rand and accepts no other
+// random source, so two setups of the same circuit never yield the same
+// keys. Reproducible keys, for test artifacts or deployed verifiers, come
+// from running the setup once and loading its output on every later run,
+// which LoadOrSetupKeys does.
+func LoadOrSetupKeys(ccs constraint.ConstraintSystem, dir string) (groth16.ProvingKey, groth16.VerifyingKey, error) {
+	if KeysExist(dir) {
+		return LoadKeys(dir, ccs)
+	}
+	pk, vk, err := SetupGroth16(ccs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := SaveKeys(ccs, pk, vk, dir); err != nil {
+		return nil, nil, err
+	}
+	return pk, vk, nil
+}
+
+// KeysExist reports whether dir holds both key files written by SaveKeys.
+func KeysExist(dir string) bool {
+	for _, name := range []string{provingKeyFile, verifyingKeyFile} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// VerifyingKeysEqual reports whether the Groth16 verifying keys a and b are
+// the same key, for instance a cached key and the one expected for the
+// current circuit. Keys are compared through their compressed encoding, so a
+// key loaded from an Uncompressed file equals the key it was saved from, and
+// keys on different curves are never equal.
+func VerifyingKeysEqual(a, b groth16.VerifyingKey) (bool, error) {
+	if a.CurveID() != b.CurveID() {
+		return false, nil
+	}
+	var bufA, bufB bytes.Buffer
+	if _, err := a.WriteTo(&bufA); err != nil {
+		return false, fmt.Errorf("serializing verifying key: %w", err)
+	}
+	if _, err := b.WriteTo(&bufB); err != nil {
+		return false, fmt.Errorf("serializing verifying key: %w", err)
+	}
+	return bytes.Equal(bufA.Bytes(), bufB.Bytes()), nil
+}
+
+// ExportCCS writes the compiled constraint system ccs to path, for other
+// gnark tools to inspect or to skip compilation on later runs.
+func ExportCCS(ccs constraint.ConstraintSystem, path string) error {
+	if err := writeToFile(path, ccs); err != nil {
+		return fmt.Errorf("saving constraint system: %w", err)
+	}
+	return nil
+}
+
+// ImportCCS reads an R1CS for curve written by ExportCCS from path, as
+// compiled for Groth16. Sparse R1CS compiled for PLONK are not supported.
+func ImportCCS(path string, curve ecc.ID) (constraint.ConstraintSystem, error) {
+	if !slices.Contains(gnark.Curves(), curve) {
+		return nil, fmt.Errorf("unsupported curve %s", curve)
+	}
+	ccs := groth16.NewCS(curve)
+	if err := readFromFile(path, ccs); err != nil {
+		return nil, fmt.Errorf("loading constraint system: %w", err)
+	}
+	// The file records the field it was compiled over, which the decoder
+	// keeps without comparing it to curve
+	if ccs.Field().Cmp(curve.ScalarField()) != 0 {
+		return nil, fmt.Errorf("constraint system in %s was not compiled for %s", path, curve)
+	}
+	return ccs, nil
+}
+
+func writeToFile(path string, src io.WriterTo) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := src.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func readFromFile(path string, dst io.ReaderFrom) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return readFrom(path, f, dst)
+}
+
+// readFrom decodes dst from r, naming the input name in errors.
+func readFrom(name string, r io.Reader, dst io.ReaderFrom) (err error) {
+	// The gnark decoders may panic on inputs they were not written for, such
+	// as points of a different curve.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%s is malformed or was written for a different curve or gnark version: %v", name, r)
+		}
+	}()
+	if _, err := dst.ReadFrom(r); err != nil {
+		return fmt.Errorf("%s is malformed or was written for a different curve or gnark version: %w", name, err)
+	}
+	return nil
+}
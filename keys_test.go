@@ -0,0 +1,257 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+)
+
+func TestSaveLoadKeys(t *testing.T) {
+	valid, _ := newTestAssignments(t)
+
+	ccs, err := CompileCircuit(ecc.BN254, backend.GROTH16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, vk, err := SetupGroth16(ccs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	if err := SaveKeys(ccs, pk, vk, dir); err != nil {
+		t.Fatal("Error saving keys:", err)
+	}
+	if !KeysExist(dir) {
+		t.Fatal("Expected saved keys to exist")
+	}
+
+	loadedPK, loadedVK, err := LoadKeys(dir, ccs)
+	if err != nil {
+		t.Fatal("Error loading keys:", err)
+	}
+	proof, err := ProveWithGroth16(ccs, loadedPK, valid)
+	if err != nil {
+		t.Fatal("Error proving with loaded key:", err)
+	}
+	if err := VerifyWithGroth16(ccs, loadedVK, proof, valid); err != nil {
+		t.Fatal("Error verifying with loaded key:", err)
+	}
+}
+
+func TestSaveKeysEncodings(t *testing.T) {
+	valid, _ := newTestAssignments(t)
+
+	ccs, err := CompileCircuit(ecc.BN254, backend.GROTH16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, vk, err := SetupGroth16(ccs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := ProveWithGroth16(ccs, pk, valid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sizes := make(map[Encoding][]int64)
+	for _, enc := range []Encoding{Compressed, Uncompressed} {
+		dir := t.TempDir()
+		proofPath := filepath.Join(dir, "proof.bin")
+		if err := SaveKeysWith(ccs, pk, vk, dir, enc); err != nil {
+			t.Fatalf("Error saving %s keys: %v", enc, err)
+		}
+		if err := SaveProofWith(ccs, proof, proofPath, enc); err != nil {
+			t.Fatalf("Error saving %s proof: %v", enc, err)
+		}
+
+		loadedPK, loadedVK, err := LoadKeys(dir, ccs)
+		if err != nil {
+			t.Fatalf("Error loading %s keys: %v", enc, err)
+		}
+		loadedProof, err := LoadProof(proofPath, ecc.BN254)
+		if err != nil {
+			t.Fatalf("Error loading %s proof: %v", enc, err)
+		}
+		if err := VerifyWithGroth16(ccs, loadedVK, loadedProof, valid); err != nil {
+			t.Fatalf("Error verifying %s proof: %v", enc, err)
+		}
+		reproved, err := ProveWithGroth16(ccs, loadedPK, valid)
+		if err != nil {
+			t.Fatalf("Error proving with %s proving key: %v", enc, err)
+		}
+		if err := VerifyWithGroth16(ccs, vk, reproved, valid); err != nil {
+			t.Fatalf("Error verifying proof made with %s proving key: %v", enc, err)
+		}
+
+		for _, path := range []string{filepath.Join(dir, provingKeyFile), filepath.Join(dir, verifyingKeyFile), proofPath} {
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sizes[enc] = append(sizes[enc], info.Size())
+		}
+	}
+	for i, name := range []string{"proving key", "verifying key", "proof"} {
+		if compressed, uncompressed := sizes[Compressed][i], sizes[Uncompressed][i]; compressed >= uncompressed {
+			t.Fatalf("Compressed %s is %d bytes, not smaller than the uncompressed %d", name, compressed, uncompressed)
+		}
+	}
+
+	if err := SaveKeysWith(ccs, pk, vk, t.TempDir(), Encoding(7)); err == nil {
+		t.Fatal("Expected an error for an unknown encoding")
+	}
+}
+
+func TestExportImportCCS(t *testing.T) {
+	valid, invalid := newTestAssignments(t)
+
+	ccs, err := CompileCircuit(ecc.BN254, backend.GROTH16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, vk, err := SetupGroth16(ccs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "eddsa.r1cs")
+	if err := ExportCCS(ccs, path); err != nil {
+		t.Fatal("Error exporting constraint system:", err)
+	}
+	imported, err := ImportCCS(path, ecc.BN254)
+	if err != nil {
+		t.Fatal("Error importing constraint system:", err)
+	}
+	if imported.GetNbConstraints() != ccs.GetNbConstraints() || imported.GetNbPublicVariables() != ccs.GetNbPublicVariables() {
+		t.Fatalf("Imported constraint system has %d constraints and %d public variables, want %d and %d",
+			imported.GetNbConstraints(), imported.GetNbPublicVariables(), ccs.GetNbConstraints(), ccs.GetNbPublicVariables())
+	}
+
+	// The imported system proves against keys set up for the original
+	proof, err := ProveWithGroth16(imported, pk, valid)
+	if err != nil {
+		t.Fatal("Error proving with imported constraint system:", err)
+	}
+	if err := VerifyWithGroth16(ccs, vk, proof, valid); err != nil {
+		t.Fatal("Error verifying proof from imported constraint system:", err)
+	}
+	if _, err := ProveWithGroth16(imported, pk, invalid); !errors.Is(err, ErrSignatureInvalid) {
+		t.Fatal("Expected ErrSignatureInvalid, got:", err)
+	}
+
+	if _, err := ImportCCS(path, ecc.BLS12_381); err == nil {
+		t.Fatal("Expected an error importing a BN254 system as BLS12-381")
+	}
+}
+
+func TestLoadKeysMalformed(t *testing.T) {
+	ccs, err := CompileCircuit(ecc.BN254, backend.GROTH16)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	for _, name := range []string{provingKeyFile, verifyingKeyFile} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("not a key"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, _, err := LoadKeys(dir, ccs); err == nil {
+		t.Fatal("Expected an error loading malformed keys")
+	}
+}
+
+func TestLoadOrSetupKeysReproducible(t *testing.T) {
+	ccs, err := CompileCircuit(ecc.BN254, backend.GROTH16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serialize := func(vk groth16.VerifyingKey) []byte {
+		t.Helper()
+		var buf bytes.Buffer
+		if _, err := vk.WriteTo(&buf); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+
+	// groth16.Setup takes no random source, so fresh setups always differ
+	_, vk1, err := SetupGroth16(ccs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, vk2, err := SetupGroth16(ccs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(serialize(vk1), serialize(vk2)) {
+		t.Fatal("Expected two setups to yield different verifying keys")
+	}
+
+	// Keys saved by the first run are what later runs get
+	dir := t.TempDir()
+	_, first, err := LoadOrSetupKeys(ccs, dir)
+	if err != nil {
+		t.Fatal("Error setting up keys:", err)
+	}
+	_, second, err := LoadOrSetupKeys(ccs, dir)
+	if err != nil {
+		t.Fatal("Error loading keys:", err)
+	}
+	if !bytes.Equal(serialize(first), serialize(second)) {
+		t.Fatal("Expected LoadOrSetupKeys to return the same verifying key on every run")
+	}
+}
+
+func TestVerifyingKeysEqual(t *testing.T) {
+	ccs, err := CompileCircuit(ecc.BN254, backend.GROTH16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, vk, err := SetupGroth16(ccs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, other, err := SetupGroth16(ccs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A key reloaded from an uncompressed file is the same key
+	dir := t.TempDir()
+	if err := SaveKeysWith(ccs, pk, vk, dir, Uncompressed); err != nil {
+		t.Fatal(err)
+	}
+	_, loaded, err := LoadKeys(dir, ccs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		a, b groth16.VerifyingKey
+		want bool
+	}{
+		{"same", vk, vk, true},
+		{"reloaded", vk, loaded, true},
+		{"other setup", vk, other, false},
+		{"other curve", vk, groth16.NewVerifyingKey(ecc.BLS12_381), false},
+	} {
+		equal, err := VerifyingKeysEqual(tc.a, tc.b)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if equal != tc.want {
+			t.Fatalf("%s: got %v, want %v", tc.name, equal, tc.want)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Events logged by the pipeline. Each is the message of an slog record, with
+// the details as attributes: "backend" names the proof system, "duration" is
+// the wall-clock time the stage took and "error" is set on failures.
+const (
+	EventCircuitCompiled = "circuit_compiled"
+	EventSetupComplete   = "setup_complete"
+	EventSetupRetry      = "setup_retry"
+	EventProofGenerated  = "proof_generated"
+	EventVerifyOK        = "verify_ok"
+	EventVerifyFailed    = "verify_failed"
+)
+
+// Events the demo logs on top of the pipeline events.
+const (
+	eventCompileStarted    = "compile_started"
+	eventSetupStarted      = "setup_started"
+	eventNativeVerifyOK    = "native_verify_ok"
+	eventKeysLoading       = "keys_loading"
+	eventKeysSaved         = "keys_saved"
+	eventSolidityExported  = "solidity_exported"
+	eventProofSaved        = "proof_saved"
+	eventProofSize         = "proof_size"
+	eventProofFileVerified = "proof_file_verified"
+	eventValidAccepted     = "valid_signature_accepted"
+	eventTamperedRejected  = "tampered_signature_rejected"
+)
+
+var pipelineLogger atomic.Pointer[slog.Logger]
+
+func init() {
+	pipelineLogger.Store(slog.New(discardHandler{}))
+}
+
+// SetLogger routes the pipeline events to l, for instance a logger with a
+// slog.JSONHandler. Events are discarded until SetLogger is called.
+func SetLogger(l *slog.Logger) {
+	pipelineLogger.Store(l)
+}
+
+func logger() *slog.Logger {
+	return pipelineLogger.Load()
+}
+
+// logVerify logs the outcome of a verification that started at start.
+func logVerify(backendName string, start time.Time, err error) {
+	if err != nil {
+		logger().Warn(EventVerifyFailed, "backend", backendName, "duration", time.Since(start), "error", err)
+		return
+	}
+	logger().Info(EventVerifyOK, "backend", backendName, "duration", time.Since(start))
+}
+
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
+// consoleHandler prints the events that have a human-readable rendering in
+// consoleMessages as single lines, and drops the others. It is what the demo
+// logs with, so that its output reads as plain status lines.
+type consoleHandler struct {
+	mu *sync.Mutex
+	w  io.Writer
+}
+
+func newConsoleHandler(w io.Writer) *consoleHandler {
+	return &consoleHandler{mu: new(sync.Mutex), w: w}
+}
+
+// consoleMessages renders events from their attributes.
+var consoleMessages = map[string]func(attrs map[string]slog.Value) string{
+	EventCircuitCompiled: func(attrs map[string]slog.Value) string {
+		return fmt.Sprint("✅ Circuit compiled with ", attrs["constraints"], " constraints")
+	},
+	eventCompileStarted: func(map[string]slog.Value) string { return "Compiling circuit..." },
+	eventSetupStarted:   func(map[string]slog.Value) string { return "Running setup..." },
+	eventNativeVerifyOK: func(map[string]slog.Value) string {
+		return "✅ Signature verified successfully outside the circuit"
+	},
+	eventKeysLoading: func(attrs map[string]slog.Value) string {
+		return fmt.Sprint("Loading keys from ", attrs["dir"])
+	},
+	eventKeysSaved: func(attrs map[string]slog.Value) string {
+		return fmt.Sprint("✅ Keys saved to ", attrs["dir"])
+	},
+	eventSolidityExported: func(attrs map[string]slog.Value) string {
+		return fmt.Sprint("✅ Solidity verifier written to ", attrs["path"])
+	},
+	eventProofSaved: func(attrs map[string]slog.Value) string {
+		return fmt.Sprint("✅ Proof written to ", attrs["proof"], " and public inputs to ", attrs["public"])
+	},
+	eventProofSize: func(attrs map[string]slog.Value) string {
+		return fmt.Sprint("Proof size: ", attrs["bytes"], " bytes")
+	},
+	eventProofFileVerified: func(attrs map[string]slog.Value) string {
+		return fmt.Sprint("✅ Proof in ", attrs["proof"], " verified")
+	},
+	eventValidAccepted: func(map[string]slog.Value) string {
+		return "✅ Proof for the valid signature verified"
+	},
+	eventTamperedRejected: func(map[string]slog.Value) string {
+		return "✅ Tampered signature failed to prove, as expected"
+	},
+}
+
+func (h *consoleHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *consoleHandler) Handle(_ context.Context, r slog.Record) error {
+	render, ok := consoleMessages[r.Message]
+	if !ok {
+		return nil
+	}
+	attrs := make(map[string]slog.Value, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs[a.Key] = a.Value
+		return true
+	})
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := fmt.Fprintln(h.w, render(attrs))
+	return err
+}
+
+func (h *consoleHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *consoleHandler) WithGroup(string) slog.Handler      { return h }
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+)
+
+func TestLoggerEvents(t *testing.T) {
+	var buf bytes.Buffer
+	SetLogger(slog.New(slog.NewJSONHandler(&buf, nil)))
+	defer SetLogger(slog.New(discardHandler{}))
+
+	ccs, err := CompileCircuit(ecc.BN254, backend.GROTH16)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var event struct {
+		Msg         string
+		Backend     string
+		Constraints int
+		Duration    int64
+	}
+	if err := json.Unmarshal(buf.Bytes(), &event); err != nil {
+		t.Fatal("Error decoding event:", err)
+	}
+	if event.Msg != EventCircuitCompiled || event.Backend != "groth16" || event.Constraints != ccs.GetNbConstraints() || event.Duration <= 0 {
+		t.Fatalf("Unexpected event %+v", event)
+	}
+}
+
+func TestConsoleHandler(t *testing.T) {
+	var buf bytes.Buffer
+	l := slog.New(newConsoleHandler(&buf))
+
+	l.Info(EventCircuitCompiled, "backend", "groth16", "constraints", 42)
+	// Events without a console rendering are dropped
+	l.Info(EventSetupComplete, "backend", "groth16")
+	l.Info(eventKeysSaved, "dir", "keys")
+
+	want := "✅ Circuit compiled with 42 constraints\n✅ Keys saved to keys\n"
+	if buf.String() != want {
+		t.Fatalf("Expected console output %q, got %q", want, buf.String())
+	}
+}
@@ -1,163 +1,533 @@
+//go:build !(js && wasm)
+
 package main
 
 import (
 	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/consensys/gnark-crypto/ecc"
 	cryptomimc "github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
 	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark-crypto/signature"
 	cryptoeddsa "github.com/consensys/gnark-crypto/signature/eddsa"
-	"github.com/consensys/gnark/frontend"
-	tedwards "github.com/consensys/gnark/std/algebra/native/twistededwards"
-	"github.com/consensys/gnark/std/signature/eddsa"
-
-	"github.com/PolyhedraZK/ExpanderCompilerCollection/ecgo"
-	"github.com/PolyhedraZK/ExpanderCompilerCollection/ecgo/test"
-	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/constraint"
+	gnarklogger "github.com/consensys/gnark/logger"
 )
 
-// EdDSACircuit defines the circuit for EdDSA signature verification
-// Using frontend.Variable for all fields to be compatible with ExpanderCompilerCollection
-type EdDSACircuit struct {
-	// Public inputs
-	PublicKeyX frontend.Variable
-	PublicKeyY frontend.Variable
-	Message    frontend.Variable
-
-	// Private inputs (witnesses)
-	SignatureR_X frontend.Variable
-	SignatureR_Y frontend.Variable
-	SignatureS   frontend.Variable
-}
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "keygen" {
+		runKeygen(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "verify" {
+		os.Exit(runVerify(os.Args[2:], os.Stdin, os.Stdout, os.Stderr))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "expander" {
+		runExpander()
+		return
+	}
 
-// Define implements the circuit for EdDSA signature verification
-func (circuit *EdDSACircuit) Define(api frontend.API) error {
-	// Initialize the twisted Edwards curve for BN254
-	curve, err := tedwards.NewEdCurve(api, twistededwards.BN254)
-	if err != nil {
-		return err
+	backendName := flag.String("backend", "groth16", "proving backend: groth16 or plonk")
+	keyDir := flag.String("keys", "", "directory to load Groth16 keys from, or to save them to after setup")
+	proveTo := flag.String("prove-to", "", "prove the valid signature with Groth16 and write the proof to this file, then exit")
+	verifyFrom := flag.String("verify-from", "", "verify the Groth16 proof in this file against the keys in -keys, then exit")
+	msgHex := flag.String("msg", "deadf00d", "hex-encoded message to sign")
+	keyPath := flag.String("key", "", "file holding the private key bytes, raw or hex, as written by keygen; a fresh key is generated if empty")
+	verifyOnly := flag.Bool("verify", false, "only sign and verify -msg with -key, skipping the tampered-signature check")
+	solidityPath := flag.String("solidity", "", "write a Solidity verifier for the Groth16 verifying key to this file, then exit")
+	analyze := flag.Bool("analyze", false, "compile the circuit and report its size without proving, then exit")
+	showTimings := flag.Bool("timings", false, "run the Groth16 pipeline once and print how long each stage took, then exit")
+	srsPath := flag.String("srs", "", "file holding a canonical KZG SRS for the PLONK setup; an unsafe test SRS is generated if empty")
+	format := flag.String("format", "text", "output format of the demo run: text, or json for one JSON object on stdout with logs on stderr")
+	flag.Parse()
+	switch *format {
+	case "text":
+		SetLogger(slog.New(newConsoleHandler(os.Stdout)))
+	case "json":
+		SetLogger(slog.New(newConsoleHandler(os.Stderr)))
+		gnarklogger.SetOutput(os.Stderr)
+	default:
+		fmt.Println("Unknown format:", *format)
+		flag.Usage()
+		os.Exit(2)
 	}
 
-	// Initialize the MiMC hash function
-	hash, err := mimc.NewMiMC(api)
-	if err != nil {
-		return err
+	// With -format json, stdout holds nothing but the report: every run
+	// ends with writeReport or exit, and diagnostics go to stderr
+	report := DemoReport{Curve: curveName(ecc.BN254), Backend: *backendName}
+	diagnostics := os.Stdout
+	if *format == "json" {
+		diagnostics = os.Stderr
 	}
 
-	// Create the public key and signature objects
-	publicKey := eddsa.PublicKey{
-		A: tedwards.Point{
-			X: circuit.PublicKeyX,
-			Y: circuit.PublicKeyY,
-		},
+	// exit ends the demo run on err with code, reporting it as text or in
+	// the JSON report. Usage errors, with code 2, also print the usage.
+	exit := func(code int, text string, err error) {
+		if err != nil {
+			fmt.Fprintln(diagnostics, text, err)
+		} else {
+			fmt.Fprintln(diagnostics, text)
+		}
+		if code == 2 {
+			flag.Usage()
+		}
+		if *format == "json" {
+			if err != nil {
+				report.Error = err.Error()
+			} else {
+				report.Error = text
+			}
+			if err := report.WriteJSON(os.Stdout); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+			}
+		}
+		os.Exit(code)
 	}
+	fail := func(text string, err error) { exit(1, text, err) }
+	usageError := func(text string, err error) { exit(2, text, err) }
 
-	signature := eddsa.Signature{
-		R: tedwards.Point{
-			X: circuit.SignatureR_X,
-			Y: circuit.SignatureR_Y,
-		},
-		S: circuit.SignatureS,
+	if *analyze {
+		nbConstraints, nbPublic, nbSecret, err := Analyze(ecc.BN254)
+		if err != nil {
+			fail("Error compiling circuit:", err)
+		}
+		sparse, err := CompileSCS(ecc.BN254)
+		if err != nil {
+			fail("Error compiling circuit:", err)
+		}
+		if *format == "json" {
+			report.Analysis = &DemoAnalysis{
+				R1CSConstraints: nbConstraints,
+				SCSConstraints:  sparse.GetNbConstraints(),
+				PublicVariables: nbPublic,
+				SecretVariables: nbSecret,
+			}
+			writeReport(*format, report)
+			return
+		}
+		fmt.Println("R1CS constraints (groth16):", nbConstraints)
+		fmt.Println("sparse R1CS constraints (plonk):", sparse.GetNbConstraints())
+		fmt.Println("public variables:", nbPublic)
+		fmt.Println("secret variables:", nbSecret)
+		return
 	}
 
-	// Verify the signature in the constraint system
-	return eddsa.Verify(curve, signature, circuit.Message, publicKey, &hash)
-}
+	if *verifyFrom != "" {
+		report.Backend = backend.GROTH16.String()
+		if *keyDir == "" {
+			usageError("-verify-from requires -keys", nil)
+		}
+		if err := verifyProofFile(*keyDir, *verifyFrom); err != nil {
+			fail("❌ Proof verification failed:", err)
+		}
+		logger().Info(eventProofFileVerified, "proof", *verifyFrom)
+		report.Valid = true
+		writeReport(*format, report)
+		return
+	}
 
-func main() {
-	fmt.Println("EdDSA Signature Verification in ZK-SNARK with ExpanderCompilerCollection")
-	fmt.Println("------------------------------------------------------------------")
+	var proofBackend backend.ID
+	switch *backendName {
+	case "groth16":
+		proofBackend = backend.GROTH16
+	case "plonk":
+		proofBackend = backend.PLONK
+	default:
+		usageError("Unknown backend: "+*backendName, nil)
+	}
 
-	// Create an EdDSA key pair
-	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if *proveTo != "" && (*keyDir == "" || proofBackend != backend.GROTH16) {
+		usageError("-prove-to requires -keys and the groth16 backend", nil)
+	}
+	if *verifyOnly && *keyPath == "" {
+		usageError("-verify requires -key", nil)
+	}
+	msg, err := decodeHex(*msgHex)
 	if err != nil {
-		fmt.Println("Error creating private key:", err)
-		os.Exit(1)
+		usageError("Invalid -msg:", err)
+	}
+	if *showTimings && proofBackend != backend.GROTH16 {
+		usageError("-timings requires the groth16 backend", nil)
+	}
+	if *solidityPath != "" && proofBackend != backend.GROTH16 {
+		usageError("-solidity requires the groth16 backend", nil)
+	}
+	if *srsPath != "" && proofBackend != backend.PLONK {
+		usageError("-srs requires the plonk backend", nil)
+	}
+
+	if *format == "text" {
+		fmt.Println("EdDSA Signature Verification in ZK-SNARK with", proofBackend)
+		fmt.Println("------------------------------------------------------------------")
+	}
+
+	// Load or create an EdDSA key pair
+	var privateKey signature.Signer
+	if *keyPath != "" {
+		privateKey, err = loadPrivateKeyFile(*keyPath)
+		if err != nil {
+			usageError("Invalid -key:", err)
+		}
+	} else {
+		privateKey, err = cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+		if err != nil {
+			fail("Error creating private key:", err)
+		}
 	}
 	publicKey := privateKey.Public()
+	pubKey := PublicKeyBytes(privateKey)
 
-	// Define a message to sign
-	msg := []byte{0xde, 0xad, 0xf0, 0x0d}
+	// Hash the message into the digest the circuit recomputes
+	digest, err := HashMessage(msg, DefaultMessageLimbs)
+	if err != nil {
+		fail("Error hashing message:", err)
+	}
 
 	// Create a MiMC hash function
 	hFunc := cryptomimc.NewMiMC()
 
-	// Sign the message
-	signature, err := privateKey.Sign(msg, hFunc)
+	// Sign the digest
+	signature, err := privateKey.Sign(digest, hFunc)
 	if err != nil {
-		fmt.Println("Error signing message:", err)
-		os.Exit(1)
+		fail("Error signing message:", err)
 	}
 
 	// Verify the signature (outside the circuit)
-	isValid, err := publicKey.Verify(signature, msg, hFunc)
+	isValid, err := publicKey.Verify(signature, digest, hFunc)
 	if err != nil {
-		fmt.Println("Error verifying signature:", err)
-		os.Exit(1)
+		fail("Error verifying signature:", err)
 	}
 	if !isValid {
-		fmt.Println("Invalid signature")
-		os.Exit(1)
+		fail("Invalid signature", nil)
 	}
-	fmt.Println("✅ Signature verified successfully outside the circuit")
+	logger().Info(eventNativeVerifyOK)
 
-	// Compile the circuit using ECC
-	fmt.Println("Compiling circuit...")
-	eccCircuit, err := ecgo.Compile(ecc.BN254.ScalarField(), &EdDSACircuit{})
-	if err != nil {
-		fmt.Println("Error compiling circuit with ECC:", err)
-		os.Exit(1)
+	if *showTimings {
+		timings, err := RunPipeline(ecc.BN254, pubKey, signature, msg)
+		report.Timings = newDemoTimings(timings)
+		if err != nil {
+			fail("❌ Pipeline failed:", err)
+		}
+		if *format == "json" {
+			report.Valid = true
+			writeReport(*format, report)
+			return
+		}
+		if err := timings.WriteTable(os.Stdout); err != nil {
+			fail("Error printing timings:", err)
+		}
+		return
 	}
 
-	// Get the layered circuit and serialize it to a file
-	layeredCircuit := eccCircuit.GetLayeredCircuit()
-	err = os.WriteFile("circuit.txt", layeredCircuit.Serialize(), 0644)
+	// Compile the circuit
+	logger().Info(eventCompileStarted)
+	report.Backend = proofBackend.String()
+	start := time.Now()
+	ccs, err := CompileCircuit(ecc.BN254, proofBackend)
 	if err != nil {
-		fmt.Println("Error writing circuit to file:", err)
-		os.Exit(1)
+		fail("Error compiling circuit:", err)
 	}
-	fmt.Println("✅ Circuit serialized to circuit.txt")
+	report.Timings.CompileMs = milliseconds(time.Since(start))
+	report.Constraints = ccs.GetNbConstraints()
 
-	// Extract public key and signature components
-	pubKey := publicKey.Bytes()
-	sig := signature
+	if *solidityPath != "" {
+		_, vk, err := loadOrSetupGroth16(ccs, *keyDir)
+		if err != nil {
+			fail("Error running setup:", err)
+		}
+		if err := exportSolidityFile(vk, *solidityPath); err != nil {
+			fail("Error exporting solidity verifier:", err)
+		}
+		logger().Info(eventSolidityExported, "path", *solidityPath)
+		writeReport(*format, report)
+		return
+	}
 
 	// Create the witness assignment
-	assignment := &EdDSACircuit{
-		// Public inputs
-		PublicKeyX: pubKey[:32], // X coordinate
-		PublicKeyY: pubKey[32:], // Y coordinate
-		Message:    msg,
+	assignment, err := NewAssignment(pubKey, signature, msg, DefaultMessageLimbs)
+	if err != nil {
+		fail("Error creating assignment:", err)
+	}
 
-		// Private inputs (witnesses)
-		SignatureR_X: sig[:32],   // R.X coordinate
-		SignatureR_Y: sig[32:64], // R.Y coordinate
-		SignatureS:   sig[64:],   // S value
+	if *proveTo != "" {
+		if err := proveToFile(ccs, *keyDir, assignment, *proveTo); err != nil {
+			fail("Error proving:", err)
+		}
+		logger().Info(eventProofSaved, "proof", *proveTo, "public", publicWitnessPath(*proveTo))
+		writeReport(*format, report)
+		return
 	}
 
-	// Get the input solver and solve for the witness
-	inputSolver := eccCircuit.GetInputSolver()
-	witness, err := inputSolver.SolveInputAuto(assignment)
+	// Run the setup once for both the valid and the tampered case
+	logger().Info(eventSetupStarted)
+	start = time.Now()
+	prove, verify, err := setupBackend(proofBackend, ccs, *keyDir, *srsPath)
 	if err != nil {
-		fmt.Println("Error solving for witness:", err)
-		os.Exit(1)
+		fail("Error running setup:", err)
+	}
+	report.Timings.SetupMs = milliseconds(time.Since(start))
+
+	start = time.Now()
+	proof, err := prove(assignment)
+	report.Timings.ProveMs = milliseconds(time.Since(start))
+	if err != nil {
+		fail("❌ Valid signature was rejected:", err)
+	}
+	start = time.Now()
+	err = verify(proof, assignment)
+	report.Timings.VerifyMs = milliseconds(time.Since(start))
+	if err != nil {
+		fail("❌ Valid signature was rejected:", err)
 	}
+	report.Valid = true
+	logger().Info(eventValidAccepted)
+	if *verifyOnly {
+		writeReport(*format, report)
+		return
+	}
+
+	// Create an invalid assignment with tampered signature
+	tamperedSignature := make([]byte, len(signature))
+	copy(tamperedSignature, signature)
+	tamperedSignature[0] ^= 0x01 // Flip a bit
 
-	// Serialize the witness to a file
-	err = os.WriteFile("witness.txt", witness.Serialize(), 0644)
+	invalidAssignment, err := NewAssignment(pubKey, tamperedSignature, msg, DefaultMessageLimbs)
 	if err != nil {
-		fmt.Println("Error writing witness to file:", err)
+		fail("Error creating assignment:", err)
+	}
+
+	proof, err = prove(invalidAssignment)
+	if err == nil {
+		err = verify(proof, invalidAssignment)
+	}
+	if err == nil {
+		fail("❌ Tampered signature was accepted", nil)
+	}
+	if !errors.Is(err, ErrSignatureInvalid) {
+		fail("❌ Proving the tampered signature failed unexpectedly:", err)
+	}
+	report.TamperedRejected = true
+	logger().Info(eventTamperedRejected)
+	writeReport(*format, report)
+}
+
+// writeReport writes the report of a successful demo run to stdout if format
+// is json. The text format reports through the log instead.
+func writeReport(format string, report DemoReport) {
+	if format != "json" {
+		return
+	}
+	if err := report.WriteJSON(os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
-	fmt.Println("✅ Witness serialized to witness.txt")
+}
+
+// setupBackend runs the setup for the given backend once and returns
+// functions that prove an assignment and verify the proof against it with
+// the resulting keys.
+// For Groth16, keys found in keyDir are reused instead of running the setup,
+// and freshly generated keys are saved there. For PLONK, the SRS is read from
+// srsPath if set.
+func setupBackend(b backend.ID, ccs constraint.ConstraintSystem, keyDir, srsPath string) (
+	prove func(assignment *EdDSACircuit) (Proof, error),
+	verify func(proof Proof, assignment *EdDSACircuit) error,
+	err error,
+) {
+	switch b {
+	case backend.GROTH16:
+		pk, vk, err := loadOrSetupGroth16(ccs, keyDir)
+		if err != nil {
+			return nil, nil, err
+		}
+		prove = func(assignment *EdDSACircuit) (Proof, error) {
+			proof, err := ProveWithGroth16(ccs, pk, assignment)
+			if err != nil {
+				return nil, err
+			}
+			return proof, logProofSize(b, proof)
+		}
+		verify = func(proof Proof, assignment *EdDSACircuit) error {
+			return VerifyWithGroth16(ccs, vk, proof.(groth16.Proof), assignment)
+		}
+		return prove, verify, nil
+	case backend.PLONK:
+		pk, vk, err := setupPlonkFrom(ccs, srsPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		prove = func(assignment *EdDSACircuit) (Proof, error) {
+			proof, err := ProveWithPlonk(ccs, pk, assignment)
+			if err != nil {
+				return nil, err
+			}
+			return proof, logProofSize(b, proof)
+		}
+		verify = func(proof Proof, assignment *EdDSACircuit) error {
+			return VerifyWithPlonk(ccs, vk, proof.(plonk.Proof), assignment)
+		}
+		return prove, verify, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported backend %s", b)
+	}
+}
 
-	// Check the circuit (this is just a local verification, not a full proof)
-	if !test.CheckCircuit(layeredCircuit, witness) {
-		fmt.Println("❌ Circuit check failed")
+// setupPlonkFrom runs the PLONK setup with the SRS in srsPath, or with an
+// unsafe test SRS if srsPath is empty.
+func setupPlonkFrom(ccs constraint.ConstraintSystem, srsPath string) (plonk.ProvingKey, plonk.VerifyingKey, error) {
+	if srsPath == "" {
+		return SetupPlonk(ccs)
+	}
+	srs, err := LoadKZGSRS(srsPath, ecc.BN254)
+	if err != nil {
+		return nil, nil, err
+	}
+	return SetupPlonkWithSRS(ccs, srs)
+}
+
+func logProofSize(b backend.ID, proof io.WriterTo) error {
+	size, err := ProofSize(proof)
+	if err != nil {
+		return err
+	}
+	logger().Info(eventProofSize, "backend", b.String(), "bytes", size)
+	return nil
+}
+
+func loadOrSetupGroth16(ccs constraint.ConstraintSystem, keyDir string) (groth16.ProvingKey, groth16.VerifyingKey, error) {
+	if keyDir != "" && KeysExist(keyDir) {
+		logger().Info(eventKeysLoading, "dir", keyDir)
+		return LoadKeys(keyDir, ccs)
+	}
+
+	pk, vk, err := SetupGroth16(ccs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if keyDir != "" {
+		if err := SaveKeys(ccs, pk, vk, keyDir); err != nil {
+			return nil, nil, err
+		}
+		logger().Info(eventKeysSaved, "dir", keyDir)
+	}
+	return pk, vk, nil
+}
+
+// publicWitnessPath returns where the public inputs for the proof at
+// proofPath are stored.
+func publicWitnessPath(proofPath string) string {
+	return proofPath + ".public"
+}
+
+// proveToFile proves assignment with Groth16 and stores the proof and its
+// public inputs next to each other, for verifyProofFile to pick up later.
+func proveToFile(ccs constraint.ConstraintSystem, keyDir string, assignment *EdDSACircuit, proofPath string) error {
+	pk, _, err := loadOrSetupGroth16(ccs, keyDir)
+	if err != nil {
+		return err
+	}
+	proof, err := ProveWithGroth16(ccs, pk, assignment)
+	if err != nil {
+		return err
+	}
+	if err := SaveProof(ccs, proof, proofPath); err != nil {
+		return err
+	}
+	return SavePublicWitness(assignment, ecc.BN254, publicWitnessPath(proofPath))
+}
+
+// verifyProofFile verifies a proof written by proveToFile with the verifying
+// key stored in keyDir, which must be for the same circuit.
+func verifyProofFile(keyDir, proofPath string) error {
+	vk, vkHeader, err := loadVerifyingKey(filepath.Join(keyDir, verifyingKeyFile), ecc.BN254)
+	if err != nil {
+		return err
+	}
+	proof, _, err := loadProof(proofPath, ecc.BN254, &vkHeader.circuit)
+	if err != nil {
+		return err
+	}
+	publicWitness, err := LoadPublicWitness(publicWitnessPath(proofPath), ecc.BN254)
+	if err != nil {
+		return err
+	}
+	return groth16Verify(proof, vk, publicWitness)
+}
+
+// runKeygen implements the keygen subcommand, which generates a BN254 key
+// pair, writes it with WriteKeyPair and prints the public key.
+func runKeygen(args []string) {
+	fs := flag.NewFlagSet("keygen", flag.ExitOnError)
+	out := fs.String("out", "eddsa", "write the private key to <out>.key and the public key to <out>.pub")
+	hexEncoding := fs.Bool("hex", false, "write the keys as hex instead of raw bytes")
+	seed := fs.String("seed", "", "derive the key deterministically from this seed, for tests and demos only")
+	fs.Parse(args)
+
+	var privateKey signature.Signer
+	var err error
+	if *seed != "" {
+		privateKey, err = NewKeyFromSeed(twistededwards.BN254, []byte(*seed))
+	} else {
+		privateKey, err = cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	}
+	if err != nil {
+		fmt.Println("Error creating private key:", err)
+		os.Exit(1)
+	}
+	if err := WriteKeyPair(privateKey, *out+".key", *out+".pub", *hexEncoding); err != nil {
+		fmt.Println("Error writing keys:", err)
 		os.Exit(1)
 	}
-	fmt.Println("✅ Circuit check passed")
-	fmt.Println("To generate and verify the actual proof, supply circuit.txt and witness.txt to Expander")
+	fmt.Println("Public key:", hex.EncodeToString(PublicKeyBytes(privateKey)))
+	fmt.Println("✅ Keys written to", *out+".key", "and", *out+".pub")
+}
+
+// runVerify implements the verify subcommand, which checks the JSON
+// VerifyRequest read from stdin with the verifying key in -vk, so that it
+// composes in shell pipelines. Only "valid" or "invalid" is written to
+// stdout; errors and the reason a proof is invalid go to stderr. It returns
+// the exit code: 0 for a valid proof, 1 for an invalid one and 2 when the
+// request or the key cannot be read.
+func runVerify(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	vkPath := fs.String("vk", "", "file holding the Groth16 verifying key, as written by SaveKeys")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *vkPath == "" {
+		fmt.Fprintln(stderr, "verify requires -vk")
+		fs.Usage()
+		return 2
+	}
+
+	vk, _, err := loadVerifyingKey(*vkPath, ecc.BN254)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error loading verifying key:", err)
+		return 2
+	}
+	resp, err := VerifyJSON(stdin, vk)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error reading request:", err)
+		return 2
+	}
+	if !resp.Valid {
+		fmt.Fprintln(stdout, "invalid")
+		fmt.Fprintln(stderr, resp.Error)
+		return 1
+	}
+	fmt.Fprintln(stdout, "valid")
+	return 0
 }
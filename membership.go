@@ -0,0 +1,135 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/accumulator/merkletree"
+	"github.com/consensys/gnark-crypto/ecc"
+	edwardsbn254 "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/accumulator/merkle"
+	tedwards "github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/signature/eddsa"
+)
+
+// MembershipEdDSACircuit proves that a signature verifies under a public key
+// belonging to an allowlist, without revealing which key signed. The
+// allowlist is a MiMC Merkle tree of depth len(Path)-1 whose leaves are the
+// hashes of the allowed public keys, built with BuildMembershipProof. Path[0]
+// is the signer's leaf and Index its position in the tree.
+type MembershipEdDSACircuit struct {
+	Root    frontend.Variable   `gnark:",public"`
+	Message []frontend.Variable `gnark:",public"`
+
+	PublicKey eddsa.PublicKey
+	Signature eddsa.Signature
+	Path      []frontend.Variable
+	Index     frontend.Variable
+}
+
+// NewMembershipCircuit returns a membership circuit for a tree of the given
+// depth and messages of nbLimbs limbs.
+func NewMembershipCircuit(depth, nbLimbs int) *MembershipEdDSACircuit {
+	return &MembershipEdDSACircuit{
+		Message: make([]frontend.Variable, nbLimbs),
+		Path:    make([]frontend.Variable, depth+1),
+	}
+}
+
+// PublicKeyLeaf returns the Merkle leaf of a BN254 public key: the MiMC hash
+// of its affine coordinates.
+func PublicKeyLeaf(pubKey []byte) ([]byte, error) {
+	var p edwardsbn254.PointAffine
+	if _, err := p.SetBytes(pubKey); err != nil {
+		return nil, fmt.Errorf("decoding public key: %w", err)
+	}
+	x, y := p.X.Bytes(), p.Y.Bytes()
+	h := newMiMCHasher()
+	// Coordinates are canonical field elements, which MiMC always accepts
+	h.Write(x[:])
+	h.Write(y[:])
+	return h.Sum(nil), nil
+}
+
+// BuildMembershipProof builds the MiMC Merkle tree of depth depth over the
+// leaves of pubKeys, padded with zero leaves, and returns its root together
+// with the Merkle path of pubKeys[index].
+func BuildMembershipProof(pubKeys [][]byte, index, depth int) (root []byte, path [][]byte, err error) {
+	nbLeaves := 1 << depth
+	if len(pubKeys) > nbLeaves {
+		return nil, nil, fmt.Errorf("%d public keys do not fit in a tree of depth %d", len(pubKeys), depth)
+	}
+	if index < 0 || index >= len(pubKeys) {
+		return nil, nil, fmt.Errorf("index %d out of range for %d public keys", index, len(pubKeys))
+	}
+
+	tree := merkletree.New(newMiMCHasher())
+	if err := tree.SetIndex(uint64(index)); err != nil {
+		return nil, nil, err
+	}
+	for i := 0; i < nbLeaves; i++ {
+		leaf := make([]byte, fieldBytes(ecc.BN254))
+		if i < len(pubKeys) {
+			if leaf, err = PublicKeyLeaf(pubKeys[i]); err != nil {
+				return nil, nil, fmt.Errorf("public key %d: %w", i, err)
+			}
+		}
+		tree.Push(leaf)
+	}
+	root, path, _, _ = tree.Prove()
+	return root, path, nil
+}
+
+// NewMembershipAssignment returns an assignment of the membership circuit
+// from the output of BuildMembershipProof and a signature produced by
+// SignMessage, with DefaultMessageLimbs message limbs.
+func NewMembershipAssignment(root []byte, path [][]byte, index int, pubKey, sig, msg []byte) (*MembershipEdDSACircuit, error) {
+	limbs, err := MessageLimbs(msg, DefaultMessageLimbs)
+	if err != nil {
+		return nil, err
+	}
+
+	assignment := &MembershipEdDSACircuit{
+		Root:    root,
+		Message: limbs,
+		Path:    make([]frontend.Variable, len(path)),
+		Index:   index,
+	}
+	for i := range path {
+		assignment.Path[i] = path[i]
+	}
+	if err := assignEncodings(twistededwards.BN254, &assignment.PublicKey, &assignment.Signature, pubKey, sig); err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
+
+// Define implements the circuit for allowlisted EdDSA signature verification
+func (circuit *MembershipEdDSACircuit) Define(api frontend.API) error {
+	if len(circuit.Path) == 0 {
+		return errors.New("merkle path must hold at least the leaf")
+	}
+
+	curve, err := tedwards.NewEdCurve(api, twistededwards.BN254)
+	if err != nil {
+		return err
+	}
+	hash, err := newMiMCFieldHasher(api)
+	if err != nil {
+		return err
+	}
+
+	// The leaf at the bottom of the path must be the signer's public key
+	hash.Write(circuit.PublicKey.A.X, circuit.PublicKey.A.Y)
+	api.AssertIsEqual(circuit.Path[0], hash.Sum())
+	hash.Reset()
+
+	// The leaf must be in the tree under Root
+	proof := merkle.MerkleProof{RootHash: circuit.Root, Path: circuit.Path}
+	proof.VerifyProof(api, hash, circuit.Index)
+	hash.Reset()
+
+	return verifyMessageSignature(curve, hash, circuit.PublicKey, circuit.Signature, circuit.Message)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	cryptoeddsa "github.com/consensys/gnark-crypto/signature/eddsa"
+	"github.com/consensys/gnark/test"
+)
+
+func TestMembershipEdDSACircuit(t *testing.T) {
+	const depth = 3
+	pubKeys, sigs, msgs := signBatch(t, 5)
+
+	const index = 3
+	root, path, err := BuildMembershipProof(pubKeys, index, depth)
+	if err != nil {
+		t.Fatal("Error building membership proof:", err)
+	}
+	assignment, err := NewMembershipAssignment(root, path, index, pubKeys[index], sigs[index], msgs[index])
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+
+	assert := test.NewAssert(t)
+	assert.SolvingSucceeded(NewMembershipCircuit(depth, DefaultMessageLimbs), assignment, test.WithCurves(ecc.BN254))
+}
+
+func TestMembershipEdDSACircuitNonMember(t *testing.T) {
+	const depth = 3
+	pubKeys, _, _ := signBatch(t, 5)
+
+	// A valid signature from a key outside the allowlist
+	outsider, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal("Error creating private key:", err)
+	}
+	msg := []byte{0xde, 0xad, 0xf0, 0x0d}
+	sig, err := SignMessage(outsider, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error signing message:", err)
+	}
+
+	// Reuse the path of a member, since the outsider has none
+	const index = 3
+	root, path, err := BuildMembershipProof(pubKeys, index, depth)
+	if err != nil {
+		t.Fatal("Error building membership proof:", err)
+	}
+	assignment, err := NewMembershipAssignment(root, path, index, outsider.Public().Bytes(), sig, msg)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+
+	assert := test.NewAssert(t)
+	assert.SolvingFailed(NewMembershipCircuit(depth, DefaultMessageLimbs), assignment, test.WithCurves(ecc.BN254))
+}
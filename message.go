@@ -0,0 +1,294 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark-crypto/signature"
+	"github.com/consensys/gnark/frontend"
+)
+
+const (
+	// MessageLimbSize is the number of message bytes packed into each field
+	// element of the circuit's Message. 31 bytes always fit below the
+	// scalar field modulus of every supported curve.
+	MessageLimbSize = 31
+
+	// DefaultMessageLimbs is the number of message limbs the circuit is
+	// compiled with, bounding messages to DefaultMessageLimbs*MessageLimbSize
+	// bytes.
+	DefaultMessageLimbs = 8
+)
+
+// MessageLimbs splits msg into nbLimbs big-endian field elements of
+// MessageLimbSize bytes each, padding with zero limbs. It fails if msg does
+// not fit in nbLimbs limbs. Any length from zero up is accepted: an empty
+// message is all zero limbs.
+//
+// The encoding does not include the message length, so messages that only
+// differ by leading zero bytes within a limb or by trailing zero limbs, such
+// as an empty message and a single 0x00 byte, share their limbs and thus
+// their signatures. Protocols where this matters must fix the message
+// length or encode it in the message.
+func MessageLimbs(msg []byte, nbLimbs int) ([]frontend.Variable, error) {
+	elements, err := messageElements(msg, nbLimbs)
+	if err != nil {
+		return nil, err
+	}
+	limbs := make([]frontend.Variable, nbLimbs)
+	for i := range elements {
+		limbs[i] = elements[i]
+	}
+	return limbs, nil
+}
+
+// AssignMessage sets the Message of assignment to the limbs of msg, keeping
+// the limb count of assignment.Message. Limbs are shorter than any supported
+// scalar field, so no message is ever reduced modulo the field: a message
+// whose bytes read as an integer at or above the modulus is assigned exactly
+// as its limbs, and one too long for the limbs is rejected rather than
+// wrapped.
+func AssignMessage(assignment *EdDSACircuit, msg []byte) error {
+	if len(assignment.Message) == 0 {
+		return errors.New("assignment has no message limbs")
+	}
+	limbs, err := MessageLimbs(msg, len(assignment.Message))
+	if err != nil {
+		return err
+	}
+	assignment.Message = limbs
+	return nil
+}
+
+// AssignMessageBigInt is AssignMessage for the message given as the
+// big-endian bytes of m, for callers holding a field element rather than
+// bytes. m must be an element of the scalar field the public key curve of
+// assignment is defined over, BN254 if unset: it is validated rather than
+// reduced, so that no two values are assigned the same message.
+func AssignMessageBigInt(assignment *EdDSACircuit, m *big.Int) error {
+	inner := assignment.Curve
+	if inner == twistededwards.UNKNOWN {
+		inner = twistededwards.BN254
+	}
+	outer, err := outerCurve(inner)
+	if err != nil {
+		return err
+	}
+	if m.Sign() < 0 || m.Cmp(outer.ScalarField()) >= 0 {
+		return fmt.Errorf("message %s is not an element of the %s scalar field", m, curveName(outer))
+	}
+	return AssignMessage(assignment, m.Bytes())
+}
+
+// AssignChunks sets the Message of assignment to chunks, field elements as
+// hashed by HashChunks. Unlike messages, chunks are not padded: there must
+// be exactly as many as the circuit has message variables, as set by
+// NewEdDSACircuit, or proving fails with an opaque witness size error, so a
+// mismatch is rejected here.
+func AssignChunks(assignment *EdDSACircuit, chunks []*big.Int) error {
+	if len(chunks) != len(assignment.Message) {
+		return fmt.Errorf("got %d message chunks, the circuit is compiled for %d", len(chunks), len(assignment.Message))
+	}
+	for i := range chunks {
+		assignment.Message[i] = chunks[i]
+	}
+	return nil
+}
+
+// HashMessage returns the MiMC digest of msg split into nbLimbs limbs. This
+// is the value the circuit computes from its Message before verifying the
+// signature, so it is what must be signed natively.
+func HashMessage(msg []byte, nbLimbs int) ([]byte, error) {
+	return HashMessageWith(HashMiMC, msg, nbLimbs)
+}
+
+// HashMessageWith is HashMessage under the hash selected by id.
+func HashMessageWith(id HashID, msg []byte, nbLimbs int) ([]byte, error) {
+	return HashMessageOn(twistededwards.BN254, id, msg, nbLimbs)
+}
+
+// HashMessageOn is HashMessageWith for keys on the twisted Edwards curve
+// inner, hashing over the scalar field that curve is defined on.
+func HashMessageOn(inner twistededwards.ID, id HashID, msg []byte, nbLimbs int) ([]byte, error) {
+	outer, err := outerCurve(inner)
+	if err != nil {
+		return nil, err
+	}
+	hFunc, err := nativeHash(inner, id)
+	if err != nil {
+		return nil, err
+	}
+	return hashLimbs(hFunc, outer, msg, nbLimbs)
+}
+
+// HashMessageKeyed is HashMessageOn under MiMC keyed with key, for a circuit
+// whose MiMCKey is key.
+func HashMessageKeyed(inner twistededwards.ID, key, msg []byte, nbLimbs int) ([]byte, error) {
+	outer, err := outerCurve(inner)
+	if err != nil {
+		return nil, err
+	}
+	hFunc, err := newKeyedMiMCHasher(inner, key)
+	if err != nil {
+		return nil, err
+	}
+	return hashLimbs(hFunc, outer, msg, nbLimbs)
+}
+
+// HashMessageUsing is HashMessageOn under the native hash returned by
+// newHasher, for a circuit whose NewHash is its in-circuit counterpart.
+func HashMessageUsing(inner twistededwards.ID, newHasher func() hash.Hash, msg []byte, nbLimbs int) ([]byte, error) {
+	outer, err := outerCurve(inner)
+	if err != nil {
+		return nil, err
+	}
+	return hashLimbs(newHasher(), outer, msg, nbLimbs)
+}
+
+// HashChunks returns the digest of chunks, field elements of the scalar
+// field inner is defined over, under the hash selected by id. Messages too
+// long for one field element are split into chunks, and both sides absorb
+// them the same way: each chunk is written to the hash as one field
+// element, in order, with no padding or length, and the digest is the
+// state after the last chunk. In the circuit, absorbChunks does the same,
+// and an EdDSACircuit whose Message holds the chunks verifies signatures
+// made with SignChunks. MessageLimbs and HashMessage are this strategy
+// applied to the limbs of a byte message.
+func HashChunks(inner twistededwards.ID, id HashID, chunks []*big.Int) ([]byte, error) {
+	outer, err := outerCurve(inner)
+	if err != nil {
+		return nil, err
+	}
+	hFunc, err := nativeHash(inner, id)
+	if err != nil {
+		return nil, err
+	}
+	return hashElements(hFunc, outer, chunks)
+}
+
+// SignChunks signs the digest computed by HashChunks with a private key on
+// inner, so that the signature verifies in a circuit whose Message holds
+// chunks.
+func SignChunks(inner twistededwards.ID, id HashID, priv signature.Signer, chunks []*big.Int) ([]byte, error) {
+	digest, err := HashChunks(inner, id, chunks)
+	if err != nil {
+		return nil, err
+	}
+	hFunc, err := nativeHash(inner, id)
+	if err != nil {
+		return nil, err
+	}
+	return priv.Sign(digest, hFunc)
+}
+
+// hashLimbs writes the nbLimbs limbs of msg to hFunc as field elements of
+// outer and returns the digest.
+func hashLimbs(hFunc hash.Hash, outer ecc.ID, msg []byte, nbLimbs int) ([]byte, error) {
+	elements, err := messageElements(msg, nbLimbs)
+	if err != nil {
+		return nil, err
+	}
+	return hashElements(hFunc, outer, elements)
+}
+
+// hashElements writes elements to hFunc as field elements of outer and
+// returns the digest.
+func hashElements(hFunc hash.Hash, outer ecc.ID, elements []*big.Int) ([]byte, error) {
+	modulus := outer.ScalarField()
+	buf := make([]byte, fieldBytes(outer))
+	for i := range elements {
+		if elements[i] == nil || elements[i].Sign() < 0 || elements[i].Cmp(modulus) >= 0 {
+			return nil, fmt.Errorf("chunk %d is not a field element of %s", i, outer)
+		}
+		if _, err := hFunc.Write(elements[i].FillBytes(buf)); err != nil {
+			return nil, fmt.Errorf("hashing message: %w", err)
+		}
+	}
+	return hFunc.Sum(nil), nil
+}
+
+// SignMessage hashes msg with HashMessage and signs the digest with MiMC, so
+// that the signature verifies in a circuit with nbLimbs message limbs.
+//
+// Signing is deterministic for keys made by the gnark-crypto eddsa package:
+// as in RFC 8032, the nonce is derived by hashing a secret 32-byte value
+// stored with the key, the last part of its Bytes, together with the digest.
+// Signing the same digest with the same key thus always yields the same
+// signature, and different digests get unrelated nonces, so no randomness is
+// needed at signing time and a weak random source cannot leak the key by
+// reusing a nonce. The secret value is as sensitive as the scalar: anyone
+// who knows it can tell the nonces of signatures and recover the key from
+// one of them. Messages sharing their limbs, and so their digest, share
+// their signature.
+func SignMessage(priv signature.Signer, msg []byte, nbLimbs int) ([]byte, error) {
+	return SignMessageWith(HashMiMC, priv, msg, nbLimbs)
+}
+
+// SignMessageWith is SignMessage under the hash selected by id, for a
+// circuit whose Hash is id.
+func SignMessageWith(id HashID, priv signature.Signer, msg []byte, nbLimbs int) ([]byte, error) {
+	return SignMessageOn(twistededwards.BN254, id, priv, msg, nbLimbs)
+}
+
+// SignMessageOn is SignMessageWith for a private key on the twisted Edwards
+// curve inner.
+func SignMessageOn(inner twistededwards.ID, id HashID, priv signature.Signer, msg []byte, nbLimbs int) ([]byte, error) {
+	digest, err := HashMessageOn(inner, id, msg, nbLimbs)
+	if err != nil {
+		return nil, err
+	}
+	hFunc, err := nativeHash(inner, id)
+	if err != nil {
+		return nil, err
+	}
+	return priv.Sign(digest, hFunc)
+}
+
+// SignMessageKeyed is SignMessageOn under MiMC keyed with key, so that the
+// signature verifies in a circuit whose MiMCKey is key and in no other.
+func SignMessageKeyed(inner twistededwards.ID, key []byte, priv signature.Signer, msg []byte, nbLimbs int) ([]byte, error) {
+	digest, err := HashMessageKeyed(inner, key, msg, nbLimbs)
+	if err != nil {
+		return nil, err
+	}
+	hFunc, err := newKeyedMiMCHasher(inner, key)
+	if err != nil {
+		return nil, err
+	}
+	return priv.Sign(digest, hFunc)
+}
+
+// SignMessageUsing is SignMessageOn under the native hash returned by
+// newHasher, so that the signature verifies in a circuit whose NewHash is
+// its in-circuit counterpart.
+func SignMessageUsing(inner twistededwards.ID, newHasher func() hash.Hash, priv signature.Signer, msg []byte, nbLimbs int) ([]byte, error) {
+	digest, err := HashMessageUsing(inner, newHasher, msg, nbLimbs)
+	if err != nil {
+		return nil, err
+	}
+	return priv.Sign(digest, newHasher())
+}
+
+// messageElements splits msg into nbLimbs limbs. Each limb holds at most
+// MessageLimbSize bytes, so it is below the scalar field modulus of every
+// supported curve.
+func messageElements(msg []byte, nbLimbs int) ([]*big.Int, error) {
+	if len(msg) > nbLimbs*MessageLimbSize {
+		return nil, fmt.Errorf("message of %d bytes exceeds the %d bytes of %d limbs", len(msg), nbLimbs*MessageLimbSize, nbLimbs)
+	}
+	elements := make([]*big.Int, nbLimbs)
+	for i := range elements {
+		elements[i] = new(big.Int)
+		start := i * MessageLimbSize
+		if start >= len(msg) {
+			continue
+		}
+		end := min(start+MessageLimbSize, len(msg))
+		elements[i].SetBytes(msg[start:end])
+	}
+	return elements, nil
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	eddsabn254 "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards/eddsa"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark-crypto/signature"
+	cryptoeddsa "github.com/consensys/gnark-crypto/signature/eddsa"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+// chunkDigestCircuit checks that absorbChunks computes the native digest of
+// Chunks.
+type chunkDigestCircuit struct {
+	Chunks []frontend.Variable
+	Digest frontend.Variable `gnark:",public"`
+}
+
+func (c *chunkDigestCircuit) Define(api frontend.API) error {
+	hash, err := newMiMCFieldHasher(api)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(absorbChunks(hash, c.Chunks), c.Digest)
+	return nil
+}
+
+func TestHashChunks(t *testing.T) {
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal("Error creating private key:", err)
+	}
+	pubKey := privateKey.Public().Bytes()
+
+	for _, n := range []int{1, 2, 10} {
+		t.Run(fmt.Sprintf("%d chunks", n), func(t *testing.T) {
+			assert := test.NewAssert(t)
+			chunks := make([]*big.Int, n)
+			variables := make([]frontend.Variable, n)
+			for i := range chunks {
+				var err error
+				if chunks[i], err = rand.Int(rand.Reader, ecc.BN254.ScalarField()); err != nil {
+					t.Fatal(err)
+				}
+				variables[i] = chunks[i]
+			}
+
+			digest, err := HashChunks(twistededwards.BN254, HashMiMC, chunks)
+			if err != nil {
+				t.Fatal("Error hashing chunks:", err)
+			}
+			circuit := &chunkDigestCircuit{Chunks: make([]frontend.Variable, n)}
+			assert.SolvingSucceeded(circuit, &chunkDigestCircuit{Chunks: variables, Digest: digest}, test.WithCurves(ecc.BN254))
+
+			// A signature over the chunks verifies with the chunks as Message
+			sig, err := SignChunks(twistededwards.BN254, HashMiMC, privateKey, chunks)
+			if err != nil {
+				t.Fatal("Error signing chunks:", err)
+			}
+			assignment := NewEdDSACircuit(n)
+			if err := AssignChunks(assignment, chunks); err != nil {
+				t.Fatal("Error assigning chunks:", err)
+			}
+			if err := assignEncodings(twistededwards.BN254, &assignment.PublicKey, &assignment.Signature, pubKey, sig); err != nil {
+				t.Fatal("Error assigning signature:", err)
+			}
+			assert.SolvingSucceeded(NewEdDSACircuit(n), assignment, test.WithCurves(ecc.BN254))
+		})
+	}
+
+	if _, err := HashChunks(twistededwards.BN254, HashMiMC, []*big.Int{ecc.BN254.ScalarField()}); err == nil {
+		t.Fatal("Expected an error for a chunk equal to the modulus")
+	}
+}
+
+func TestAssignChunksCount(t *testing.T) {
+	chunks := []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)}
+	for _, n := range []int{2, 4} {
+		const want = "got 3 message chunks, the circuit is compiled for "
+		err := AssignChunks(NewEdDSACircuit(n), chunks)
+		if err == nil || err.Error() != fmt.Sprint(want, n) {
+			t.Fatalf("AssignChunks to %d variables: error = %v, want %q", n, err, fmt.Sprint(want, n))
+		}
+	}
+	if err := AssignChunks(NewEdDSACircuit(3), chunks); err != nil {
+		t.Fatal("Error assigning as many chunks as variables:", err)
+	}
+}
+
+func TestSignMessageDeterministic(t *testing.T) {
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal("Error creating private key:", err)
+	}
+	msg := []byte{0xde, 0xad, 0xf0, 0x0d}
+	sig, err := SignMessage(privateKey, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error signing message:", err)
+	}
+
+	// The same key, also once restored from its bytes, signs the same
+	// message identically
+	restored := new(eddsabn254.PrivateKey)
+	if _, err := restored.SetBytes(privateKey.Bytes()); err != nil {
+		t.Fatal("Error restoring private key:", err)
+	}
+	for _, key := range []signature.Signer{privateKey, restored} {
+		again, err := SignMessage(key, msg, DefaultMessageLimbs)
+		if err != nil {
+			t.Fatal("Error signing message:", err)
+		}
+		if !bytes.Equal(again, sig) {
+			t.Fatal("Expected signing the same message with the same key to give the same signature")
+		}
+	}
+
+	other, err := SignMessage(privateKey, []byte{0xba, 0xd0}, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error signing message:", err)
+	}
+	if bytes.Equal(other[:32], sig[:32]) {
+		t.Fatal("Expected different messages to be signed with different nonces")
+	}
+}
+
+func TestAssignMessageBigInt(t *testing.T) {
+	modulus := ecc.BN254.ScalarField()
+	for _, m := range []*big.Int{
+		big.NewInt(0),
+		big.NewInt(0xdeadf00d),
+		new(big.Int).Lsh(big.NewInt(1), 8*MessageLimbSize),
+		new(big.Int).Sub(modulus, big.NewInt(1)),
+	} {
+		got := NewEdDSACircuit(DefaultMessageLimbs)
+		if err := AssignMessageBigInt(got, m); err != nil {
+			t.Fatalf("Error assigning %s: %v", m, err)
+		}
+		want := NewEdDSACircuit(DefaultMessageLimbs)
+		if err := AssignMessage(want, m.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(got.Message) != fmt.Sprint(want.Message) {
+			t.Fatalf("Assigned %v for %s, want %v", got.Message, m, want.Message)
+		}
+	}
+
+	for _, m := range []*big.Int{big.NewInt(-1), modulus} {
+		if err := AssignMessageBigInt(NewEdDSACircuit(DefaultMessageLimbs), m); err == nil {
+			t.Fatalf("Expected an error assigning %s", m)
+		}
+	}
+}
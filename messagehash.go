@@ -0,0 +1,65 @@
+package main
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	tedwards "github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/signature/eddsa"
+)
+
+// MessageHashEdDSACircuit verifies a signature made with SignMessage, like
+// EdDSACircuit on BN254, and exposes the MiMC digest of the Message limbs as
+// the public MessageHash, so that an outer circuit or a verifier can bind to
+// the digest that was signed. The Message itself is secret.
+type MessageHashEdDSACircuit struct {
+	PublicKey   eddsa.PublicKey   `gnark:",public"`
+	Signature   eddsa.Signature   `gnark:",public"`
+	MessageHash frontend.Variable `gnark:",public"`
+
+	Message []frontend.Variable
+}
+
+// NewMessageHashCircuit returns a message hash circuit whose Message holds
+// nbLimbs limbs, for use both as the compilation template and as an
+// assignment.
+func NewMessageHashCircuit(nbLimbs int) *MessageHashEdDSACircuit {
+	return &MessageHashEdDSACircuit{Message: make([]frontend.Variable, nbLimbs)}
+}
+
+// NewMessageHashAssignment returns an assignment of the message hash circuit
+// with nbLimbs message limbs for a BN254 public key, a signature produced by
+// SignMessage and the original message. MessageHash is set to the digest
+// computed by HashMessage.
+func NewMessageHashAssignment(pubKey, sig, msg []byte, nbLimbs int) (*MessageHashEdDSACircuit, error) {
+	limbs, err := MessageLimbs(msg, nbLimbs)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := HashMessage(msg, nbLimbs)
+	if err != nil {
+		return nil, err
+	}
+	assignment := &MessageHashEdDSACircuit{Message: limbs, MessageHash: new(big.Int).SetBytes(digest)}
+	if err := assignEncodings(twistededwards.BN254, &assignment.PublicKey, &assignment.Signature, pubKey, sig); err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
+
+// Define implements the circuit for EdDSA verification of a message whose
+// digest is public
+func (circuit *MessageHashEdDSACircuit) Define(api frontend.API) error {
+	curve, err := tedwards.NewEdCurve(api, twistededwards.BN254)
+	if err != nil {
+		return err
+	}
+	hash, err := newMiMCFieldHasher(api)
+	if err != nil {
+		return err
+	}
+
+	api.AssertIsEqual(absorbChunks(hash, circuit.Message), circuit.MessageHash)
+	return eddsa.Verify(curve, circuit.Signature, circuit.MessageHash, circuit.PublicKey, hash)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	cryptoeddsa "github.com/consensys/gnark-crypto/signature/eddsa"
+	"github.com/consensys/gnark/test"
+)
+
+func TestMessageHashEdDSACircuit(t *testing.T) {
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal("Error creating private key:", err)
+	}
+	pubKey := privateKey.Public().Bytes()
+	msg := []byte{0xde, 0xad, 0xf0, 0x0d}
+	sig, err := SignMessage(privateKey, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error signing message:", err)
+	}
+	assignment, err := NewMessageHashAssignment(pubKey, sig, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+
+	assert := test.NewAssert(t)
+	assert.SolvingSucceeded(NewMessageHashCircuit(DefaultMessageLimbs), assignment, test.WithCurves(ecc.BN254))
+
+	// A wrong MessageHash must fail even though the signature is valid
+	wrongHash := *assignment
+	wrongHash.MessageHash = new(big.Int).Add(assignment.MessageHash.(*big.Int), big.NewInt(1))
+	assert.SolvingFailed(NewMessageHashCircuit(DefaultMessageLimbs), &wrongHash, test.WithCurves(ecc.BN254))
+
+	// So must another message under the signed MessageHash
+	other, err := NewMessageHashAssignment(pubKey, sig, []byte{0xba, 0xd0}, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+	other.MessageHash = assignment.MessageHash
+	assert.SolvingFailed(NewMessageHashCircuit(DefaultMessageLimbs), other, test.WithCurves(ecc.BN254))
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"expvar"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// durationBuckets are the upper bounds, in seconds, of the buckets of a
+// Histogram. Groth16 proofs of the EdDSA circuit take around a second and
+// verifications a few milliseconds, so the buckets span both.
+var durationBuckets = [...]float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 2.5, 5, 10, 30}
+
+// Histogram counts durations in the buckets of durationBuckets, plus one for
+// longer durations. It is an expvar.Var rendering as a JSON object with the
+// total count, the sum in seconds and the cumulative count of each bucket,
+// keyed by its upper bound as in Prometheus. The zero value is ready to use
+// and safe for concurrent use.
+type Histogram struct {
+	mu     sync.Mutex
+	counts [len(durationBuckets) + 1]int64
+	sum    float64
+}
+
+// Observe adds d to the histogram.
+func (h *Histogram) Observe(d time.Duration) {
+	s := d.Seconds()
+	i := 0
+	for i < len(durationBuckets) && s > durationBuckets[i] {
+		i++
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.counts[i]++
+	h.sum += s
+}
+
+// Count returns the number of durations observed.
+func (h *Histogram) Count() int64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var n int64
+	for _, c := range h.counts {
+		n += c
+	}
+	return n
+}
+
+func (h *Histogram) String() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var b strings.Builder
+	var cumulative int64
+	b.WriteString(`{"buckets": {`)
+	for i, c := range h.counts {
+		cumulative += c
+		bound := "+Inf"
+		if i < len(durationBuckets) {
+			bound = strconv.FormatFloat(durationBuckets[i], 'g', -1, 64)
+		}
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%q: %d", bound, cumulative)
+	}
+	fmt.Fprintf(&b, `}, "count": %d, "sum": %s}`, cumulative, strconv.FormatFloat(h.sum, 'g', -1, 64))
+	return b.String()
+}
+
+// Metrics instruments a Verifier configured with it: it counts the proofs
+// generated and the verifications that succeeded or failed, and records how
+// long proving and verifying take. Failed proofs are only timed. Its fields
+// are expvar variables, safe for concurrent use, which Publish exposes on
+// the /debug/vars endpoint of expvar.
+type Metrics struct {
+	ProofsGenerated        expvar.Int
+	VerificationsSucceeded expvar.Int
+	VerificationsFailed    expvar.Int
+	ProveDuration          Histogram
+	VerifyDuration         Histogram
+}
+
+// Map returns the variables of m in an expvar.Map, keyed by their names in
+// snake case.
+func (m *Metrics) Map() *expvar.Map {
+	vars := new(expvar.Map)
+	vars.Set("proofs_generated", &m.ProofsGenerated)
+	vars.Set("verifications_succeeded", &m.VerificationsSucceeded)
+	vars.Set("verifications_failed", &m.VerificationsFailed)
+	vars.Set("prove_duration_seconds", &m.ProveDuration)
+	vars.Set("verify_duration_seconds", &m.VerifyDuration)
+	return vars
+}
+
+// Publish publishes Map under name with expvar.Publish, which panics if name
+// is already in use.
+func (m *Metrics) Publish(name string) {
+	expvar.Publish(name, m.Map())
+}
+
+// observeProve records a proof that started at start and ended with err. It
+// does nothing on a nil m.
+func (m *Metrics) observeProve(start time.Time, err error) {
+	if m == nil {
+		return
+	}
+	m.ProveDuration.Observe(time.Since(start))
+	if err == nil {
+		m.ProofsGenerated.Add(1)
+	}
+}
+
+// observeVerify records a verification that started at start and ended with
+// err. It does nothing on a nil m.
+func (m *Metrics) observeVerify(start time.Time, err error) {
+	if m == nil {
+		return
+	}
+	m.VerifyDuration.Observe(time.Since(start))
+	if err != nil {
+		m.VerificationsFailed.Add(1)
+		return
+	}
+	m.VerificationsSucceeded.Add(1)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	cryptoeddsa "github.com/consensys/gnark-crypto/signature/eddsa"
+)
+
+func TestVerifierMetrics(t *testing.T) {
+	metrics := new(Metrics)
+	v, err := NewVerifierWithConfig(ecc.BN254, VerifierConfig{Metrics: metrics})
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey := privateKey.Public().Bytes()
+	msg := []byte{0xde, 0xad, 0xf0, 0x0d}
+
+	proof, sig, err := SignAndProve(privateKey, msg, v)
+	if err != nil {
+		t.Fatal("Error signing and proving:", err)
+	}
+	if err := v.Verify(proof, pubKey, sig, msg); err != nil {
+		t.Fatal("Error verifying:", err)
+	}
+	if err := v.Verify(proof, pubKey, sig, []byte{0xba, 0xd0}); err == nil {
+		t.Fatal("Expected verification against another message to fail")
+	}
+
+	for name, got := range map[string]int64{
+		"proofs generated":        metrics.ProofsGenerated.Value(),
+		"verifications succeeded": metrics.VerificationsSucceeded.Value(),
+		"verifications failed":    metrics.VerificationsFailed.Value(),
+		"prove durations":         metrics.ProveDuration.Count(),
+	} {
+		if got != 1 {
+			t.Fatalf("Expected 1 for %s, got %d", name, got)
+		}
+	}
+	if got := metrics.VerifyDuration.Count(); got != 2 {
+		t.Fatalf("Expected 2 verify durations, got %d", got)
+	}
+
+	// The published map must be valid JSON for /debug/vars
+	var vars map[string]any
+	if err := json.Unmarshal([]byte(metrics.Map().String()), &vars); err != nil {
+		t.Fatalf("Error decoding %s: %v", metrics.Map(), err)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	var h Histogram
+	h.Observe(2 * time.Millisecond)
+	h.Observe(time.Minute)
+
+	var out struct {
+		Buckets map[string]int64 `json:"buckets"`
+		Count   int64            `json:"count"`
+		Sum     float64          `json:"sum"`
+	}
+	if err := json.Unmarshal([]byte(h.String()), &out); err != nil {
+		t.Fatalf("Error decoding %s: %v", h.String(), err)
+	}
+	if out.Count != 2 || out.Buckets["0.001"] != 0 || out.Buckets["0.005"] != 1 || out.Buckets["30"] != 1 || out.Buckets["+Inf"] != 2 {
+		t.Fatalf("Unexpected histogram %s", h.String())
+	}
+	if out.Sum != 60.002 {
+		t.Fatalf("Expected a sum of 60.002 seconds, got %v", out.Sum)
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark-crypto/signature"
+	"github.com/consensys/gnark/frontend"
+	tedwards "github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/signature/eddsa"
+)
+
+// nonceDomain is the domain whose DomainTag NonceEdDSACircuit absorbs
+// first, so that its signatures are not those of EdDSACircuit over one more
+// limb holding the nonce, nor the other way around.
+const nonceDomain = "eddsa-gnark nonce"
+
+// NonceEdDSACircuit verifies a signature over H(tag || message || nonce),
+// the MiMC digest of the tag of nonceDomain, the Message limbs and Nonce. A
+// verifier that accepts each nonce only once rejects replays of a signed
+// message, since the signature does not verify under any other nonce.
+type NonceEdDSACircuit struct {
+	PublicKey eddsa.PublicKey     `gnark:",public"`
+	Signature eddsa.Signature     `gnark:",public"`
+	Message   []frontend.Variable `gnark:",public"`
+	Nonce     frontend.Variable   `gnark:",public"`
+}
+
+// NewNonceCircuit returns a nonce circuit whose Message holds nbLimbs limbs,
+// for use both as the compilation template and as an assignment.
+func NewNonceCircuit(nbLimbs int) *NonceEdDSACircuit {
+	return &NonceEdDSACircuit{Message: make([]frontend.Variable, nbLimbs)}
+}
+
+// HashMessageNonce returns the MiMC digest of the nonce domain tag, msg
+// split into nbLimbs limbs and nonce, matching NonceEdDSACircuit.
+func HashMessageNonce(msg []byte, nonce uint64, nbLimbs int) ([]byte, error) {
+	tag, err := DomainTag(nonceDomain)
+	if err != nil {
+		return nil, err
+	}
+	elements, err := messageElements(msg, nbLimbs)
+	if err != nil {
+		return nil, err
+	}
+	elements = append([]*big.Int{tag.BigInt(new(big.Int))}, elements...)
+	elements = append(elements, new(big.Int).SetUint64(nonce))
+	return hashElements(mimc.NewMiMC(), ecc.BN254, elements)
+}
+
+// SignMessageNonce signs the digest computed by HashMessageNonce with a
+// BN254 private key.
+func SignMessageNonce(priv signature.Signer, msg []byte, nonce uint64, nbLimbs int) ([]byte, error) {
+	digest, err := HashMessageNonce(msg, nonce, nbLimbs)
+	if err != nil {
+		return nil, err
+	}
+	return priv.Sign(digest, mimc.NewMiMC())
+}
+
+// NewNonceAssignment returns an assignment of the nonce circuit with nbLimbs
+// message limbs for a BN254 public key and a signature produced by
+// SignMessageNonce.
+func NewNonceAssignment(pubKey, sig, msg []byte, nonce uint64, nbLimbs int) (*NonceEdDSACircuit, error) {
+	limbs, err := MessageLimbs(msg, nbLimbs)
+	if err != nil {
+		return nil, err
+	}
+	assignment := &NonceEdDSACircuit{Message: limbs, Nonce: nonce}
+	if err := assignEncodings(twistededwards.BN254, &assignment.PublicKey, &assignment.Signature, pubKey, sig); err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
+
+// Define implements the circuit for EdDSA verification of a message bound to
+// a nonce
+func (circuit *NonceEdDSACircuit) Define(api frontend.API) error {
+	curve, err := tedwards.NewEdCurve(api, twistededwards.BN254)
+	if err != nil {
+		return err
+	}
+	hash, err := newMiMCFieldHasher(api)
+	if err != nil {
+		return err
+	}
+	tag, err := DomainTag(nonceDomain)
+	if err != nil {
+		return err
+	}
+
+	// The domain tag is absorbed first and the nonce after the message limbs
+	fields := append([]frontend.Variable{tag}, circuit.Message...)
+	fields = append(fields, circuit.Nonce)
+	return verifyMessageSignature(curve, hash, circuit.PublicKey, circuit.Signature, fields)
+}
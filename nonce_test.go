@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"slices"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	cryptoeddsa "github.com/consensys/gnark-crypto/signature/eddsa"
+	"github.com/consensys/gnark/test"
+)
+
+func TestNonceEdDSACircuit(t *testing.T) {
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal("Error creating private key:", err)
+	}
+	pubKey := privateKey.Public().Bytes()
+	msg := []byte{0xde, 0xad, 0xf0, 0x0d}
+
+	const nonce = 42
+	sig, err := SignMessageNonce(privateKey, msg, nonce, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error signing message:", err)
+	}
+	assignment, err := NewNonceAssignment(pubKey, sig, msg, nonce, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+
+	assert := test.NewAssert(t)
+	assert.SolvingSucceeded(NewNonceCircuit(DefaultMessageLimbs), assignment, test.WithCurves(ecc.BN254))
+
+	// Replaying the signature under another nonce must fail
+	replayed, err := NewNonceAssignment(pubKey, sig, msg, nonce+1, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+	assert.SolvingFailed(NewNonceCircuit(DefaultMessageLimbs), replayed, test.WithCurves(ecc.BN254))
+
+	// A plain signature over the message does not verify under any nonce
+	plain, err := SignMessage(privateKey, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error signing message:", err)
+	}
+	unbound, err := NewNonceAssignment(pubKey, plain, msg, nonce, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+	assert.SolvingFailed(NewNonceCircuit(DefaultMessageLimbs), unbound, test.WithCurves(ecc.BN254))
+}
+
+func TestNonceRejectsPlainSignature(t *testing.T) {
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal("Error creating private key:", err)
+	}
+	pubKey := privateKey.Public().Bytes()
+
+	// A message filling every limb, followed by the nonce as one more limb,
+	// gives a plain message whose limbs are those the nonce circuit hashes
+	const nonce = 42
+	msg := bytes.Repeat([]byte{0xab}, DefaultMessageLimbs*MessageLimbSize)
+	plainMsg := binary.BigEndian.AppendUint64(slices.Clone(msg), nonce)
+	plainSig, err := SignMessage(privateKey, plainMsg, DefaultMessageLimbs+1)
+	if err != nil {
+		t.Fatal("Error signing message:", err)
+	}
+	plain, err := NewAssignment(pubKey, plainSig, plainMsg, DefaultMessageLimbs+1)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+	assert := test.NewAssert(t)
+	assert.SolvingSucceeded(NewEdDSACircuit(DefaultMessageLimbs+1), plain, test.WithCurves(ecc.BN254))
+
+	unbound, err := NewNonceAssignment(pubKey, plainSig, msg, nonce, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+	assert.SolvingFailed(NewNonceCircuit(DefaultMessageLimbs), unbound, test.WithCurves(ecc.BN254))
+
+	// Nor does a nonce signature verify as a plain one
+	nonceSig, err := SignMessageNonce(privateKey, msg, nonce, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error signing message:", err)
+	}
+	replayed, err := NewAssignment(pubKey, nonceSig, plainMsg, DefaultMessageLimbs+1)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+	assert.SolvingFailed(NewEdDSACircuit(DefaultMessageLimbs+1), replayed, test.WithCurves(ecc.BN254))
+}
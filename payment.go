@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	edwardsbn254 "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark-crypto/signature"
+	"github.com/consensys/gnark/frontend"
+	tedwards "github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/signature/eddsa"
+)
+
+// paymentDomain is the domain whose DomainTag PaymentEdDSACircuit absorbs
+// first, so that its signatures verify in no other circuit with the same
+// layout of field elements, such as TransferCircuit.
+const paymentDomain = "eddsa-gnark payment"
+
+// PaymentEdDSACircuit verifies a signature over H(tag || pubKey || nonce ||
+// amount), the MiMC digest of the tag of paymentDomain and the signer's
+// public key coordinates X and Y followed by Nonce and Amount. Binding the
+// key into the digest ties the signature to its signer, and the nonce makes
+// every signed payment unique.
+type PaymentEdDSACircuit struct {
+	PublicKey eddsa.PublicKey   `gnark:",public"`
+	Signature eddsa.Signature   `gnark:",public"`
+	Nonce     frontend.Variable `gnark:",public"`
+	Amount    frontend.Variable `gnark:",public"`
+}
+
+// HashPayment returns the MiMC digest of the payment domain tag and the
+// coordinates of the BN254 public key pubKey followed by nonce and amount,
+// matching PaymentEdDSACircuit.
+func HashPayment(pubKey []byte, nonce, amount uint64) ([]byte, error) {
+	tag, err := DomainTag(paymentDomain)
+	if err != nil {
+		return nil, err
+	}
+	var a edwardsbn254.PointAffine
+	if _, err := a.SetBytes(pubKey); err != nil {
+		return nil, fmt.Errorf("decoding public key: %w", err)
+	}
+	var n, v fr.Element
+	n.SetUint64(nonce)
+	v.SetUint64(amount)
+
+	hFunc := mimc.NewMiMC()
+	for _, e := range []*fr.Element{&tag, &a.X, &a.Y, &n, &v} {
+		b := e.Bytes()
+		if _, err := hFunc.Write(b[:]); err != nil {
+			return nil, fmt.Errorf("hashing payment: %w", err)
+		}
+	}
+	return hFunc.Sum(nil), nil
+}
+
+// SignPayment signs the digest computed by HashPayment for the public key
+// of priv.
+func SignPayment(priv signature.Signer, nonce, amount uint64) ([]byte, error) {
+	digest, err := HashPayment(priv.Public().Bytes(), nonce, amount)
+	if err != nil {
+		return nil, err
+	}
+	return priv.Sign(digest, mimc.NewMiMC())
+}
+
+// NewPaymentAssignment returns an assignment of PaymentEdDSACircuit for a
+// BN254 public key and a signature produced by SignPayment.
+func NewPaymentAssignment(pubKey, sig []byte, nonce, amount uint64) (*PaymentEdDSACircuit, error) {
+	assignment := &PaymentEdDSACircuit{Nonce: nonce, Amount: amount}
+	if err := assignEncodings(twistededwards.BN254, &assignment.PublicKey, &assignment.Signature, pubKey, sig); err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
+
+// Define implements the circuit for EdDSA verification of a signed payment
+func (circuit *PaymentEdDSACircuit) Define(api frontend.API) error {
+	curve, err := tedwards.NewEdCurve(api, twistededwards.BN254)
+	if err != nil {
+		return err
+	}
+	hash, err := newMiMCFieldHasher(api)
+	if err != nil {
+		return err
+	}
+	tag, err := DomainTag(paymentDomain)
+	if err != nil {
+		return err
+	}
+
+	// The domain tag is absorbed first, then the signed fields in a fixed
+	// order
+	fields := []frontend.Variable{tag, circuit.PublicKey.A.X, circuit.PublicKey.A.Y, circuit.Nonce, circuit.Amount}
+	return verifyMessageSignature(curve, hash, circuit.PublicKey, circuit.Signature, fields)
+}
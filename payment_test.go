@@ -0,0 +1,39 @@
+package main
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	cryptoeddsa "github.com/consensys/gnark-crypto/signature/eddsa"
+	"github.com/consensys/gnark/test"
+)
+
+func TestPaymentEdDSACircuit(t *testing.T) {
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal("Error creating private key:", err)
+	}
+	pubKey := privateKey.Public().Bytes()
+
+	const nonce, amount = 17, 250_000_000
+	sig, err := SignPayment(privateKey, nonce, amount)
+	if err != nil {
+		t.Fatal("Error signing payment:", err)
+	}
+	assignment, err := NewPaymentAssignment(pubKey, sig, nonce, amount)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+
+	assert := test.NewAssert(t)
+	assert.SolvingSucceeded(&PaymentEdDSACircuit{}, assignment, test.WithCurves(ecc.BN254))
+
+	// The same signature must not verify for an altered amount
+	altered, err := NewPaymentAssignment(pubKey, sig, nonce, amount+1)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+	assert.SolvingFailed(&PaymentEdDSACircuit{}, altered, test.WithCurves(ecc.BN254))
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+)
+
+// Timings holds the wall-clock duration of each stage of a Groth16 run of
+// the EdDSA circuit.
+type Timings struct {
+	Compile    time.Duration
+	Setup      time.Duration
+	NewWitness time.Duration
+	Prove      time.Duration
+	Verify     time.Duration
+}
+
+// Total returns the sum of all stage durations.
+func (t Timings) Total() time.Duration {
+	return t.Compile + t.Setup + t.NewWitness + t.Prove + t.Verify
+}
+
+// WriteTable writes t to w as a table with one row per stage.
+func (t Timings) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "stage\tduration\t")
+	for _, row := range []struct {
+		stage string
+		d     time.Duration
+	}{
+		{"compile", t.Compile},
+		{"setup", t.Setup},
+		{"witness", t.NewWitness},
+		{"prove", t.Prove},
+		{"verify", t.Verify},
+		{"total", t.Total()},
+	} {
+		fmt.Fprintf(tw, "%s\t%s\t\n", row.stage, row.d.Round(time.Microsecond))
+	}
+	return tw.Flush()
+}
+
+// RunPipeline compiles the EdDSA circuit for curve, runs the Groth16 setup,
+// then builds the witness for pubKey, sig and msg, proves and verifies it,
+// timing each stage. On error, the returned Timings hold the stages that
+// completed.
+func RunPipeline(curve ecc.ID, pubKey, sig, msg []byte) (Timings, error) {
+	var t Timings
+	inner, err := InnerCurve(curve)
+	if err != nil {
+		return t, err
+	}
+	assignment, err := NewAssignmentOn(inner, pubKey, sig, msg, DefaultMessageLimbs)
+	if err != nil {
+		return t, err
+	}
+
+	start := time.Now()
+	ccs, err := CompileCircuit(curve, backend.GROTH16)
+	if err != nil {
+		return t, err
+	}
+	t.Compile = time.Since(start)
+
+	start = time.Now()
+	pk, vk, err := SetupGroth16(ccs)
+	if err != nil {
+		return t, err
+	}
+	t.Setup = time.Since(start)
+
+	start = time.Now()
+	fullWitness, err := frontend.NewWitness(assignment, ccs.Field())
+	if err != nil {
+		return t, fmt.Errorf("creating witness: %w", err)
+	}
+	publicWitness, err := fullWitness.Public()
+	if err != nil {
+		return t, fmt.Errorf("creating public witness: %w", err)
+	}
+	t.NewWitness = time.Since(start)
+
+	start = time.Now()
+	proof, err := groth16Prove(ccs, pk, fullWitness)
+	if err != nil {
+		return t, wrapProveError("groth16", err)
+	}
+	t.Prove = time.Since(start)
+
+	start = time.Now()
+	if err := groth16Verify(proof, vk, publicWitness); err != nil {
+		return t, fmt.Errorf("groth16 verify: %w", err)
+	}
+	t.Verify = time.Since(start)
+	return t, nil
+}
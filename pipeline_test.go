@@ -0,0 +1,39 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+func TestRunPipeline(t *testing.T) {
+	pubKeys, sigs, msgs := signBatch(t, 1)
+
+	timings, err := RunPipeline(ecc.BN254, pubKeys[0], sigs[0], msgs[0])
+	if err != nil {
+		t.Fatal("Error running pipeline:", err)
+	}
+	for stage, d := range map[string]time.Duration{
+		"compile": timings.Compile,
+		"setup":   timings.Setup,
+		"witness": timings.NewWitness,
+		"prove":   timings.Prove,
+		"verify":  timings.Verify,
+	} {
+		if d <= 0 {
+			t.Fatalf("Expected the %s stage to be timed", stage)
+		}
+	}
+
+	// A tampered signature stops the pipeline at the prove stage
+	sigs[0][0] ^= 0x01
+	timings, err = RunPipeline(ecc.BN254, pubKeys[0], sigs[0], msgs[0])
+	if !errors.Is(err, ErrSignatureInvalid) {
+		t.Fatal("Expected ErrSignatureInvalid, got:", err)
+	}
+	if timings.Setup <= 0 || timings.Prove != 0 {
+		t.Fatalf("Expected timings up to the prove stage, got %+v", timings)
+	}
+}
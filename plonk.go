@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	kzgbls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/kzg"
+	kzgbls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/kzg"
+	kzgbls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/kzg"
+	kzgbn254 "github.com/consensys/gnark-crypto/ecc/bn254/kzg"
+	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test/unsafekzg"
+)
+
+// SetupPlonk runs the PLONK setup for ccs.
+//
+// The KZG SRS is generated with unsafekzg, whose toxic waste is known: keys
+// produced this way are only suitable for testing and benchmarking.
+func SetupPlonk(ccs constraint.ConstraintSystem) (plonk.ProvingKey, plonk.VerifyingKey, error) {
+	start := time.Now()
+	srs, srsLagrange, err := unsafekzg.NewSRS(ccs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generating kzg srs: %w", err)
+	}
+	return setupPlonk(ccs, srs, srsLagrange, start)
+}
+
+// SetupPlonkWithSRS runs the PLONK setup for ccs with srs, the canonical
+// KZG SRS from a trusted setup ceremony, as loaded by LoadKZGSRS. The SRS
+// must hold at least as many G1 points as the circuit needs.
+func SetupPlonkWithSRS(ccs constraint.ConstraintSystem, srs kzg.SRS) (plonk.ProvingKey, plonk.VerifyingKey, error) {
+	start := time.Now()
+	srsLagrange, err := lagrangeSRS(srs, plonkSRSSize(ccs))
+	if err != nil {
+		return nil, nil, err
+	}
+	return setupPlonk(ccs, srs, srsLagrange, start)
+}
+
+func setupPlonk(ccs constraint.ConstraintSystem, srs, srsLagrange kzg.SRS, start time.Time) (plonk.ProvingKey, plonk.VerifyingKey, error) {
+	pk, vk, err := plonk.Setup(ccs, srs, srsLagrange)
+	if err != nil {
+		return nil, nil, fmt.Errorf("plonk setup: %w", err)
+	}
+	logger().Info(EventSetupComplete, "backend", "plonk", "duration", time.Since(start))
+	return pk, vk, nil
+}
+
+// LoadKZGSRS reads a canonical KZG SRS for curve from path, in the binary
+// format written by the gnark-crypto SRS WriteTo method.
+func LoadKZGSRS(path string, curve ecc.ID) (kzg.SRS, error) {
+	if _, err := InnerCurve(curve); err != nil {
+		return nil, err
+	}
+	srs := kzg.NewSRS(curve)
+	if err := readFromFile(path, srs); err != nil {
+		return nil, fmt.Errorf("loading kzg srs: %w", err)
+	}
+	return srs, nil
+}
+
+// plonkSRSSize returns the size of the Lagrange SRS the PLONK setup needs
+// for ccs, a power of two. The canonical SRS needs three more points.
+func plonkSRSSize(ccs constraint.ConstraintSystem) int {
+	return int(ecc.NextPowerOfTwo(uint64(ccs.GetNbConstraints() + ccs.GetNbPublicVariables())))
+}
+
+// lagrangeSRS returns the Lagrange form of the first size points of the
+// canonical srs, after checking that srs has the size+3 points PLONK needs.
+func lagrangeSRS(srs kzg.SRS, size int) (kzg.SRS, error) {
+	checkSize := func(n int) error {
+		if n < size+3 {
+			return fmt.Errorf("kzg srs has %d G1 points, the circuit needs %d", n, size+3)
+		}
+		return nil
+	}
+	switch s := srs.(type) {
+	case *kzgbn254.SRS:
+		if err := checkSize(len(s.Pk.G1)); err != nil {
+			return nil, err
+		}
+		g1, err := kzgbn254.ToLagrangeG1(append(s.Pk.G1[:0:0], s.Pk.G1[:size]...))
+		if err != nil {
+			return nil, fmt.Errorf("converting kzg srs to lagrange form: %w", err)
+		}
+		return &kzgbn254.SRS{Pk: kzgbn254.ProvingKey{G1: g1}, Vk: s.Vk}, nil
+	case *kzgbls12381.SRS:
+		if err := checkSize(len(s.Pk.G1)); err != nil {
+			return nil, err
+		}
+		g1, err := kzgbls12381.ToLagrangeG1(append(s.Pk.G1[:0:0], s.Pk.G1[:size]...))
+		if err != nil {
+			return nil, fmt.Errorf("converting kzg srs to lagrange form: %w", err)
+		}
+		return &kzgbls12381.SRS{Pk: kzgbls12381.ProvingKey{G1: g1}, Vk: s.Vk}, nil
+	case *kzgbls12377.SRS:
+		if err := checkSize(len(s.Pk.G1)); err != nil {
+			return nil, err
+		}
+		g1, err := kzgbls12377.ToLagrangeG1(append(s.Pk.G1[:0:0], s.Pk.G1[:size]...))
+		if err != nil {
+			return nil, fmt.Errorf("converting kzg srs to lagrange form: %w", err)
+		}
+		return &kzgbls12377.SRS{Pk: kzgbls12377.ProvingKey{G1: g1}, Vk: s.Vk}, nil
+	case *kzgbls24315.SRS:
+		if err := checkSize(len(s.Pk.G1)); err != nil {
+			return nil, err
+		}
+		g1, err := kzgbls24315.ToLagrangeG1(append(s.Pk.G1[:0:0], s.Pk.G1[:size]...))
+		if err != nil {
+			return nil, fmt.Errorf("converting kzg srs to lagrange form: %w", err)
+		}
+		return &kzgbls24315.SRS{Pk: kzgbls24315.ProvingKey{G1: g1}, Vk: s.Vk}, nil
+	default:
+		return nil, fmt.Errorf("unsupported kzg srs type %T", srs)
+	}
+}
+
+// ProveWithPlonk builds the full witness for assignment and proves it
+// against ccs with the PLONK proving key pk. ccs must have been compiled
+// with the sparse R1CS builder. An unsatisfied assignment fails with
+// ErrSignatureInvalid.
+func ProveWithPlonk(ccs constraint.ConstraintSystem, pk plonk.ProvingKey, assignment frontend.Circuit) (plonk.Proof, error) {
+	if err := checkAssignmentCurve(ccs.Field(), assignment); err != nil {
+		return nil, err
+	}
+	fullWitness, err := frontend.NewWitness(assignment, ccs.Field())
+	if err != nil {
+		return nil, fmt.Errorf("creating witness: %w", err)
+	}
+
+	start := time.Now()
+	proof, err := plonkProve(ccs, pk, fullWitness)
+	if err != nil {
+		return nil, wrapProveError("plonk", err)
+	}
+	logger().Info(EventProofGenerated, "backend", "plonk", "duration", time.Since(start))
+	return proof, nil
+}
+
+// VerifyWithPlonk checks proof against the public part of assignment.
+func VerifyWithPlonk(ccs constraint.ConstraintSystem, vk plonk.VerifyingKey, proof plonk.Proof, assignment frontend.Circuit) error {
+	if err := checkAssignmentCurve(ccs.Field(), assignment); err != nil {
+		return err
+	}
+	publicWitness, err := frontend.NewWitness(assignment, ccs.Field(), frontend.PublicOnly())
+	if err != nil {
+		return fmt.Errorf("creating public witness: %w", err)
+	}
+	return verifyPlonkPublicWitness(vk, proof, publicWitness)
+}
+
+// plonkProve is plonk.Prove with panics returned as ErrProverPanic.
+func plonkProve(ccs constraint.ConstraintSystem, pk plonk.ProvingKey, fullWitness witness.Witness) (proof plonk.Proof, err error) {
+	defer recoverPanic(&err)
+	return plonk.Prove(ccs, pk, fullWitness)
+}
+
+// plonkVerify is plonk.Verify with panics returned as ErrProverPanic.
+func plonkVerify(proof plonk.Proof, vk plonk.VerifyingKey, publicWitness witness.Witness) (err error) {
+	defer recoverPanic(&err)
+	return plonk.Verify(proof, vk, publicWitness)
+}
+
+// verifyPlonkPublicWitness checks proof against vk and publicWitness.
+func verifyPlonkPublicWitness(vk plonk.VerifyingKey, proof plonk.Proof, publicWitness witness.Witness) error {
+	start := time.Now()
+	err := plonkVerify(proof, vk, publicWitness)
+	logVerify("plonk", start, err)
+	if err != nil {
+		return fmt.Errorf("plonk verify: %w", err)
+	}
+	return nil
+}
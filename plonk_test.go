@@ -0,0 +1,142 @@
+package main
+
+import (
+	"crypto/rand"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	kzgbn254 "github.com/consensys/gnark-crypto/ecc/bn254/kzg"
+	"github.com/consensys/gnark/backend"
+)
+
+func TestPlonkProveVerify(t *testing.T) {
+	valid, invalid := newTestAssignments(t)
+
+	ccs, err := CompileCircuit(ecc.BN254, backend.PLONK)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, vk, err := SetupPlonk(ccs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	proof, err := ProveWithPlonk(ccs, pk, valid)
+	if err != nil {
+		t.Fatal("Error proving valid signature:", err)
+	}
+	if err := VerifyWithPlonk(ccs, vk, proof, valid); err != nil {
+		t.Fatal("Error verifying valid signature:", err)
+	}
+
+	// The tampered signature must not even produce a proof
+	if _, err := ProveWithPlonk(ccs, pk, invalid); !errors.Is(err, ErrSignatureInvalid) {
+		t.Fatal("Expected ErrSignatureInvalid, got:", err)
+	}
+}
+
+func TestSetupPlonkWithSRS(t *testing.T) {
+	valid, _ := newTestAssignments(t)
+
+	ccs, err := CompileCircuit(ecc.BN254, backend.PLONK)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Stand in for the output of a ceremony with a freshly sampled secret
+	newSRSFile := func(size int) string {
+		tau, err := rand.Int(rand.Reader, ecc.BN254.ScalarField())
+		if err != nil {
+			t.Fatal(err)
+		}
+		srs, err := kzgbn254.NewSRS(uint64(size), tau)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(t.TempDir(), "srs.bin")
+		if err := writeToFile(path, srs); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	srs, err := LoadKZGSRS(newSRSFile(plonkSRSSize(ccs)+3), ecc.BN254)
+	if err != nil {
+		t.Fatal("Error loading SRS:", err)
+	}
+	pk, vk, err := SetupPlonkWithSRS(ccs, srs)
+	if err != nil {
+		t.Fatal("Error in setup:", err)
+	}
+	proof, err := ProveWithPlonk(ccs, pk, valid)
+	if err != nil {
+		t.Fatal("Error proving valid signature:", err)
+	}
+	if err := VerifyWithPlonk(ccs, vk, proof, valid); err != nil {
+		t.Fatal("Error verifying valid signature:", err)
+	}
+
+	// An SRS smaller than the circuit is rejected before the setup
+	small, err := LoadKZGSRS(newSRSFile(plonkSRSSize(ccs)), ecc.BN254)
+	if err != nil {
+		t.Fatal("Error loading SRS:", err)
+	}
+	if _, _, err := SetupPlonkWithSRS(ccs, small); err == nil || !strings.Contains(err.Error(), "G1 points") {
+		t.Fatal("Expected an error for a too small SRS, got:", err)
+	}
+}
+
+// BenchmarkProve compares proving time and proof size of Groth16 and PLONK
+// for the same EdDSA circuit.
+func BenchmarkProve(b *testing.B) {
+	valid, _ := newTestAssignments(b)
+
+	b.Run("groth16", func(b *testing.B) {
+		ccs, err := CompileCircuit(ecc.BN254, backend.GROTH16)
+		if err != nil {
+			b.Fatal(err)
+		}
+		pk, _, err := SetupGroth16(ccs)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			proof, err := ProveWithGroth16(ccs, pk, valid)
+			if err != nil {
+				b.Fatal(err)
+			}
+			size, err := ProofSize(proof)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ReportMetric(float64(size), "proof-bytes")
+		}
+	})
+
+	b.Run("plonk", func(b *testing.B) {
+		ccs, err := CompileCircuit(ecc.BN254, backend.PLONK)
+		if err != nil {
+			b.Fatal(err)
+		}
+		pk, _, err := SetupPlonk(ccs)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			proof, err := ProveWithPlonk(ccs, pk, valid)
+			if err != nil {
+				b.Fatal(err)
+			}
+			size, err := ProofSize(proof)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ReportMetric(float64(size), "proof-bytes")
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+
+	edwardsbls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/twistededwards"
+	edwardsbls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/twistededwards"
+	edwardsbls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/twistededwards"
+	edwardsbn254 "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark-crypto/signature"
+)
+
+// PointMessageLimbs is the number of message limbs of an EdDSACircuit whose
+// message is a curve point, as built by NewPointMessageAssignment.
+const PointMessageLimbs = 2
+
+// PointMessage returns the chunks a compressed point on inner, such as a
+// public key, is signed as: its affine X and Y coordinates, which are field
+// elements of the scalar field inner is defined over. Hashing the
+// coordinates rather than the bytes of the encoding absorbs each as exactly
+// one field element, on both sides, with no limb splitting.
+func PointMessage(inner twistededwards.ID, point []byte) ([]*big.Int, error) {
+	var coords []*big.Int
+	var err error
+	switch inner {
+	case twistededwards.BN254:
+		coords, err = decodePointWith(point, func(p *edwardsbn254.PointAffine) []*big.Int { return affineCoordinates(&p.X, &p.Y) })
+	case twistededwards.BLS12_381:
+		coords, err = decodePointWith(point, func(p *edwardsbls12381.PointAffine) []*big.Int { return affineCoordinates(&p.X, &p.Y) })
+	case twistededwards.BLS12_377:
+		coords, err = decodePointWith(point, func(p *edwardsbls12377.PointAffine) []*big.Int { return affineCoordinates(&p.X, &p.Y) })
+	case twistededwards.BLS24_315:
+		coords, err = decodePointWith(point, func(p *edwardsbls24315.PointAffine) []*big.Int { return affineCoordinates(&p.X, &p.Y) })
+	default:
+		return nil, fmt.Errorf("unsupported twisted Edwards curve ID %d", inner)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decoding point message: %w", err)
+	}
+	return coords, nil
+}
+
+// affineCoordinates returns the coordinates x and y of a point as integers.
+func affineCoordinates(x, y interface{ BigInt(*big.Int) *big.Int }) []*big.Int {
+	return []*big.Int{x.BigInt(new(big.Int)), y.BigInt(new(big.Int))}
+}
+
+// SignPoint signs the compressed point on inner with a private key on inner,
+// under MiMC, so that the signature verifies in an EdDSACircuit with
+// PointMessageLimbs limbs assigned by NewPointMessageAssignment.
+func SignPoint(inner twistededwards.ID, priv signature.Signer, point []byte) ([]byte, error) {
+	chunks, err := PointMessage(inner, point)
+	if err != nil {
+		return nil, err
+	}
+	return SignChunks(inner, HashMiMC, priv, chunks)
+}
+
+// NewPointMessageAssignment returns an assignment of the circuit with
+// PointMessageLimbs message limbs for a public key and a signature produced
+// by SignPoint on inner, and the signed compressed point.
+func NewPointMessageAssignment(inner twistededwards.ID, pubKey, sig, point []byte) (*EdDSACircuit, error) {
+	chunks, err := PointMessage(inner, point)
+	if err != nil {
+		return nil, err
+	}
+	assignment := NewEdDSACircuit(PointMessageLimbs)
+	assignment.Curve = inner
+	if err := AssignChunks(assignment, chunks); err != nil {
+		return nil, err
+	}
+	if err := assignEncodings(inner, &assignment.PublicKey, &assignment.Signature, pubKey, sig); err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	cryptoeddsa "github.com/consensys/gnark-crypto/signature/eddsa"
+	"github.com/consensys/gnark/test"
+)
+
+func TestSignPoint(t *testing.T) {
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal("Error creating private key:", err)
+	}
+	// The message is the public key of another, random key
+	points := make([][]byte, 2)
+	for i := range points {
+		other, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+		if err != nil {
+			t.Fatal("Error creating private key:", err)
+		}
+		points[i] = other.Public().Bytes()
+	}
+
+	sig, err := SignPoint(twistededwards.BN254, privateKey, points[0])
+	if err != nil {
+		t.Fatal("Error signing point:", err)
+	}
+	pubKey := privateKey.Public().Bytes()
+	assignment, err := NewPointMessageAssignment(twistededwards.BN254, pubKey, sig, points[0])
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+	assert := test.NewAssert(t)
+	assert.SolvingSucceeded(NewEdDSACircuit(PointMessageLimbs), assignment, test.WithCurves(ecc.BN254))
+
+	// The signature does not verify for another point
+	assignment, err = NewPointMessageAssignment(twistededwards.BN254, pubKey, sig, points[1])
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+	assert.SolvingFailed(NewEdDSACircuit(PointMessageLimbs), assignment, test.WithCurves(ecc.BN254))
+
+	// Bytes that are not a point are rejected
+	if _, err := SignPoint(twistededwards.BN254, privateKey, []byte{0xde, 0xad}); err == nil {
+		t.Fatal("Expected an error signing bytes that are not a point")
+	}
+}
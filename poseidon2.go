@@ -0,0 +1,107 @@
+package main
+
+import (
+	"errors"
+	"hash"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	cryptoposeidon2 "github.com/consensys/gnark-crypto/ecc/bn254/fr/poseidon2"
+	"github.com/consensys/gnark/frontend"
+	stdhash "github.com/consensys/gnark/std/hash"
+	stdposeidon2 "github.com/consensys/gnark/std/permutation/poseidon2"
+)
+
+// Neither gnark-crypto nor gnark ship a Poseidon2 hash for BN254 in the
+// pinned versions, only the permutation. Both sides below build the same
+// Merkle-Damgård construction on top of it: starting from a zero state h,
+// each input element m is absorbed as h = P(h, m)[1] + m.
+const (
+	poseidon2Width         = 2
+	poseidon2FullRounds    = 6
+	poseidon2PartialRounds = 50
+	poseidon2Seed          = "Poseidon2 hash for BN254 with t=2, rF=6, rP=50, d=5"
+)
+
+// poseidon2Hasher is the native Poseidon2 hash. Like gnark-crypto's MiMC it
+// reads its input as big-endian field elements of fr.Bytes bytes each, and
+// left-pads a single short write.
+type poseidon2Hasher struct {
+	perm cryptoposeidon2.Hash
+	data []fr.Element
+}
+
+func newPoseidon2Hasher() hash.Hash {
+	return &poseidon2Hasher{
+		perm: cryptoposeidon2.NewHash(poseidon2Width, poseidon2FullRounds, poseidon2PartialRounds, poseidon2Seed),
+	}
+}
+
+func (h *poseidon2Hasher) Write(p []byte) (int, error) {
+	if len(p) > 0 && len(p) < fr.Bytes {
+		pp := make([]byte, fr.Bytes)
+		copy(pp[len(pp)-len(p):], p)
+		p = pp
+	}
+	if len(p)%fr.Bytes != 0 {
+		return 0, errors.New("invalid input length: must represent a list of field elements, expects a []byte of len m*fr.Bytes")
+	}
+	for start := 0; start < len(p); start += fr.Bytes {
+		elem, err := fr.BigEndian.Element((*[fr.Bytes]byte)(p[start : start+fr.Bytes]))
+		if err != nil {
+			return 0, err
+		}
+		h.data = append(h.data, elem)
+	}
+	return len(p), nil
+}
+
+func (h *poseidon2Hasher) Sum(b []byte) []byte {
+	var state fr.Element
+	for i := range h.data {
+		buf := []fr.Element{state, h.data[i]}
+		// The width always matches the permutation, so it cannot fail
+		_ = h.perm.Permutation(buf)
+		state.Add(&buf[1], &h.data[i])
+	}
+	digest := state.Bytes()
+	return append(b, digest[:]...)
+}
+
+func (h *poseidon2Hasher) Reset()         { h.data = h.data[:0] }
+func (h *poseidon2Hasher) Size() int      { return fr.Bytes }
+func (h *poseidon2Hasher) BlockSize() int { return fr.Bytes }
+
+// poseidon2FieldHasher is the in-circuit counterpart of poseidon2Hasher.
+type poseidon2FieldHasher struct {
+	api  frontend.API
+	perm stdposeidon2.Hash
+	data []frontend.Variable
+}
+
+func newPoseidon2FieldHasher(api frontend.API) (stdhash.FieldHasher, error) {
+	if api.Compiler().Field().Cmp(ecc.BN254.ScalarField()) != 0 {
+		return nil, errors.New("poseidon2 is only available on BN254")
+	}
+	return &poseidon2FieldHasher{
+		api:  api,
+		perm: stdposeidon2.NewHash(poseidon2Width, 5, poseidon2FullRounds, poseidon2PartialRounds, poseidon2Seed, ecc.BN254),
+	}, nil
+}
+
+func (h *poseidon2FieldHasher) Write(data ...frontend.Variable) {
+	h.data = append(h.data, data...)
+}
+
+func (h *poseidon2FieldHasher) Sum() frontend.Variable {
+	var state frontend.Variable = 0
+	for _, m := range h.data {
+		buf := []frontend.Variable{state, m}
+		// The width always matches the permutation, so it cannot fail
+		_ = h.perm.Permutation(h.api, buf)
+		state = h.api.Add(buf[1], m)
+	}
+	return state
+}
+
+func (h *poseidon2FieldHasher) Reset() { h.data = nil }
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	cryptoeddsa "github.com/consensys/gnark-crypto/signature/eddsa"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+// poseidon2DigestCircuit checks that the in-circuit Poseidon2 hash computes
+// the native digest of Inputs.
+type poseidon2DigestCircuit struct {
+	Inputs []frontend.Variable
+	Digest frontend.Variable `gnark:",public"`
+}
+
+func (c *poseidon2DigestCircuit) Define(api frontend.API) error {
+	hash, err := newPoseidon2FieldHasher(api)
+	if err != nil {
+		return err
+	}
+	hash.Write(c.Inputs...)
+	api.AssertIsEqual(hash.Sum(), c.Digest)
+	return nil
+}
+
+func TestPoseidon2NativeMatchesCircuit(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 9} {
+		t.Run(fmt.Sprintf("%d inputs", n), func(t *testing.T) {
+			inputs := make([]*big.Int, n)
+			variables := make([]frontend.Variable, n)
+			for i := range inputs {
+				var err error
+				if inputs[i], err = rand.Int(rand.Reader, ecc.BN254.ScalarField()); err != nil {
+					t.Fatal(err)
+				}
+				variables[i] = inputs[i]
+			}
+			digest, err := HashChunks(twistededwards.BN254, HashPoseidon2, inputs)
+			if err != nil {
+				t.Fatal("Error hashing inputs:", err)
+			}
+
+			assert := test.NewAssert(t)
+			circuit := &poseidon2DigestCircuit{Inputs: make([]frontend.Variable, n)}
+			assert.SolvingSucceeded(circuit, &poseidon2DigestCircuit{Inputs: variables, Digest: digest}, test.WithCurves(ecc.BN254))
+		})
+	}
+}
+
+func TestPoseidon2Groth16(t *testing.T) {
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal("Error creating private key:", err)
+	}
+	pubKey := privateKey.Public().Bytes()
+	msg := []byte("a message signed under poseidon2")
+	sig, err := SignMessageWith(HashPoseidon2, privateKey, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error signing message:", err)
+	}
+	assignment, err := NewAssignment(pubKey, sig, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+
+	circuit := NewEdDSACircuit(DefaultMessageLimbs)
+	circuit.Hash = HashPoseidon2
+	ccs, err := compileCircuit(ecc.BN254, backend.GROTH16, circuit)
+	if err != nil {
+		t.Fatal("Error compiling circuit:", err)
+	}
+	pk, vk, err := SetupGroth16(ccs)
+	if err != nil {
+		t.Fatal("Error in setup:", err)
+	}
+	proof, err := ProveWithGroth16(ccs, pk, assignment)
+	if err != nil {
+		t.Fatal("Error proving:", err)
+	}
+	if err := VerifyWithGroth16(ccs, vk, proof, assignment); err != nil {
+		t.Fatal("Error verifying:", err)
+	}
+	if err := VerifyProof(vk, proof, pubKey, sig, []byte("another message")); err == nil {
+		t.Fatal("Expected an error verifying the proof for another message")
+	}
+}
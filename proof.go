@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+)
+
+// SaveProof writes a Groth16 proof for ccs to path, after a header
+// recording the curve and the CircuitVersion of ccs. Points are compressed;
+// see SaveProofWith.
+func SaveProof(ccs constraint.ConstraintSystem, proof groth16.Proof, path string) error {
+	return SaveProofWith(ccs, proof, path, Compressed)
+}
+
+// SaveProofWith is SaveProof writing points with the encoding enc.
+func SaveProofWith(ccs constraint.ConstraintSystem, proof groth16.Proof, path string, enc Encoding) error {
+	src, err := enc.encode(proof)
+	if err != nil {
+		return err
+	}
+	h, err := newFileHeader(ccs)
+	if err != nil {
+		return err
+	}
+	if err := writeWithHeader(path, h, src); err != nil {
+		return fmt.Errorf("saving proof: %w", err)
+	}
+	return nil
+}
+
+// LoadProof reads a Groth16 proof for curve written by SaveProof or
+// SaveProofWith from path. A proof whose header records another curve is
+// rejected.
+func LoadProof(path string, curve ecc.ID) (groth16.Proof, error) {
+	proof, _, err := loadProof(path, curve, nil)
+	return proof, err
+}
+
+// loadProof is LoadProof also checking the circuit version when circuit is
+// not nil, and returning the header.
+func loadProof(path string, curve ecc.ID, circuit *CircuitVersion) (groth16.Proof, fileHeader, error) {
+	proof := groth16.NewProof(curve)
+	h, err := readWithHeader(path, curve, circuit, proof)
+	if err != nil {
+		return nil, fileHeader{}, fmt.Errorf("loading proof: %w", err)
+	}
+	return proof, h, nil
+}
+
+// ProofToBase64 returns the standard base64 encoding of proof as written by
+// WriteTo, for embedding in JSON and other text protocols. Unlike SaveProof,
+// it writes no header.
+func ProofToBase64(proof groth16.Proof) (string, error) {
+	var buf bytes.Buffer
+	if _, err := proof.WriteTo(&buf); err != nil {
+		return "", fmt.Errorf("serializing proof: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// ProofFromBase64 decodes a Groth16 proof for curve encoded by
+// ProofToBase64.
+func ProofFromBase64(s string, curve ecc.ID) (groth16.Proof, error) {
+	proof := groth16.NewProof(curve)
+	if err := readBase64("proof", s, proof); err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+// ProofSize returns the size in bytes of the encoding of proof, which is
+// what SaveProof writes after its header. It accepts both Groth16 and PLONK
+// proofs.
+func ProofSize(proof io.WriterTo) (int, error) {
+	n, err := proof.WriteTo(io.Discard)
+	if err != nil {
+		return 0, fmt.Errorf("serializing proof: %w", err)
+	}
+	return int(n), nil
+}
+
+// SavePublicWitness writes the public part of assignment to path, so that a
+// separate process can verify a proof without knowing the original inputs.
+func SavePublicWitness(assignment frontend.Circuit, curve ecc.ID, path string) error {
+	publicWitness, err := frontend.NewWitness(assignment, curve.ScalarField(), frontend.PublicOnly())
+	if err != nil {
+		return fmt.Errorf("creating public witness: %w", err)
+	}
+	if err := writeToFile(path, publicWitness); err != nil {
+		return fmt.Errorf("saving public witness: %w", err)
+	}
+	return nil
+}
+
+// LoadPublicWitness reads a public witness for curve written by
+// SavePublicWitness from path.
+func LoadPublicWitness(path string, curve ecc.ID) (witness.Witness, error) {
+	publicWitness, err := witness.New(curve.ScalarField())
+	if err != nil {
+		return nil, fmt.Errorf("creating public witness: %w", err)
+	}
+	if err := readFromFile(path, publicWitness); err != nil {
+		return nil, fmt.Errorf("loading public witness: %w", err)
+	}
+	return publicWitness, nil
+}
+
+// VerifyFromFiles verifies the Groth16 proof at proofPath, written by
+// SaveProof, against the verifying key at vkPath, written by SaveKeys, and
+// the raw inputs pubKey, sig and msg. It is all a deployed verifier needs:
+// neither the constraint system nor the proving key is loaded. A verifying
+// key for another curve than curve, or a proof for another circuit than the
+// verifying key, is reported as such.
+func VerifyFromFiles(vkPath, proofPath string, curve ecc.ID, pubKey, sig, msg []byte) error {
+	inner, err := InnerCurve(curve)
+	if err != nil {
+		return err
+	}
+	vk, vkHeader, err := loadVerifyingKey(vkPath, curve)
+	if err != nil {
+		return err
+	}
+	proof, _, err := loadProof(proofPath, curve, &vkHeader.circuit)
+	if err != nil {
+		return err
+	}
+	if err := ValidatePublicKey(inner, pubKey); err != nil {
+		return fmt.Errorf("invalid public key: %w", err)
+	}
+	return VerifyProof(vk, proof, pubKey, sig, msg)
+}
+
+// loadVerifyingKey reads a verifying key for curve written by SaveKeys from
+// path, along with its header.
+func loadVerifyingKey(path string, curve ecc.ID) (groth16.VerifyingKey, fileHeader, error) {
+	vk := groth16.NewVerifyingKey(curve)
+	h, err := readWithHeader(path, curve, nil, vk)
+	if err != nil {
+		return nil, fileHeader{}, fmt.Errorf("loading verifying key: %w", err)
+	}
+	return vk, h, nil
+}
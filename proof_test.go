@@ -0,0 +1,149 @@
+package main
+
+import (
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	cryptoeddsa "github.com/consensys/gnark-crypto/signature/eddsa"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+)
+
+func TestSaveLoadProof(t *testing.T) {
+	valid, _ := newTestAssignments(t)
+
+	ccs, err := CompileCircuit(ecc.BN254, backend.GROTH16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, vk, err := SetupGroth16(ccs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := ProveWithGroth16(ccs, pk, valid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	proofPath := filepath.Join(dir, "proof.bin")
+	witnessPath := filepath.Join(dir, "proof.bin.public")
+	if err := SaveProof(ccs, proof, proofPath); err != nil {
+		t.Fatal("Error saving proof:", err)
+	}
+	size, err := ProofSize(proof)
+	if err != nil {
+		t.Fatal("Error measuring proof:", err)
+	}
+	if info, err := os.Stat(proofPath); err != nil || info.Size() != int64(fileHeaderSize+size) {
+		t.Fatalf("ProofSize = %d, does not match the saved proof: %v, %v", size, info, err)
+	}
+	if err := SavePublicWitness(valid, ecc.BN254, witnessPath); err != nil {
+		t.Fatal("Error saving public witness:", err)
+	}
+
+	loadedProof, err := LoadProof(proofPath, ecc.BN254)
+	if err != nil {
+		t.Fatal("Error loading proof:", err)
+	}
+	publicWitness, err := LoadPublicWitness(witnessPath, ecc.BN254)
+	if err != nil {
+		t.Fatal("Error loading public witness:", err)
+	}
+	if err := groth16.Verify(loadedProof, vk, publicWitness); err != nil {
+		t.Fatal("Error verifying loaded proof:", err)
+	}
+}
+
+func TestProofBase64(t *testing.T) {
+	valid, _ := newTestAssignments(t)
+
+	ccs, err := CompileCircuit(ecc.BN254, backend.GROTH16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, vk, err := SetupGroth16(ccs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := ProveWithGroth16(ccs, pk, valid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encoded, err := ProofToBase64(proof)
+	if err != nil {
+		t.Fatal("Error encoding proof:", err)
+	}
+	decoded, err := ProofFromBase64(encoded, ecc.BN254)
+	if err != nil {
+		t.Fatal("Error decoding proof:", err)
+	}
+	if err := VerifyWithGroth16(ccs, vk, decoded, valid); err != nil {
+		t.Fatal("Error verifying decoded proof:", err)
+	}
+
+	if _, err := ProofFromBase64("not base64!", ecc.BN254); err == nil {
+		t.Fatal("Expected an error decoding invalid base64")
+	}
+	if _, err := ProofFromBase64(encoded[:len(encoded)/2], ecc.BN254); err == nil {
+		t.Fatal("Expected an error decoding a truncated proof")
+	}
+}
+
+func TestVerifyFromFiles(t *testing.T) {
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey := privateKey.Public().Bytes()
+	msg := []byte{0xde, 0xad, 0xf0, 0x0d}
+	sig, err := SignMessage(privateKey, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assignment, err := NewAssignment(pubKey, sig, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ccs, err := CompileCircuit(ecc.BN254, backend.GROTH16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, vk, err := SetupGroth16(ccs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := ProveWithGroth16(ccs, pk, assignment)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	proofPath := filepath.Join(dir, "proof.bin")
+	if err := SaveKeys(ccs, pk, vk, dir); err != nil {
+		t.Fatal("Error saving keys:", err)
+	}
+	if err := SaveProof(ccs, proof, proofPath); err != nil {
+		t.Fatal("Error saving proof:", err)
+	}
+	vkPath := filepath.Join(dir, verifyingKeyFile)
+
+	if err := VerifyFromFiles(vkPath, proofPath, ecc.BN254, pubKey, sig, msg); err != nil {
+		t.Fatal("Error verifying from files:", err)
+	}
+	if err := VerifyFromFiles(vkPath, proofPath, ecc.BN254, pubKey, sig, []byte{0xba, 0xd0}); err == nil {
+		t.Fatal("Expected verification against another message to fail")
+	}
+
+	err = VerifyFromFiles(vkPath, proofPath, ecc.BLS12_381, pubKey, sig, msg)
+	if err == nil || !strings.Contains(err.Error(), "written for bn254") {
+		t.Fatal("Expected a curve mismatch error, got:", err)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+)
+
+// ProveMany proves each of witnesses, full witnesses of independent
+// statements, against ccs with the Groth16 proving key pk on a pool of
+// workers goroutines. It returns one proof and one error per witness, in
+// order; an unsatisfied witness fails with ErrSignatureInvalid.
+//
+// groth16.Prove only reads ccs and pk, solving each witness with a solver of
+// its own, so the workers share them; neither must be modified until
+// ProveMany returns. Each groth16.Prove already spreads its multi-scalar
+// multiplications and FFTs over every CPU, so a few workers are usually
+// enough to keep them busy, and each one holds the memory of a proof in
+// flight.
+func ProveMany(ccs constraint.ConstraintSystem, pk groth16.ProvingKey, witnesses []witness.Witness, workers int) ([]groth16.Proof, []error) {
+	workers = max(1, min(workers, len(witnesses)))
+	proofs := make([]groth16.Proof, len(witnesses))
+	errs := make([]error, len(witnesses))
+
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				start := time.Now()
+				proof, err := groth16Prove(ccs, pk, witnesses[i])
+				if err != nil {
+					errs[i] = wrapProveError("groth16", err)
+					continue
+				}
+				logger().Info(EventProofGenerated, "backend", "groth16", "duration", time.Since(start))
+				proofs[i] = proof
+			}
+		}()
+	}
+	for i := range witnesses {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+	return proofs, errs
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+)
+
+// proveManySetup signs n messages and returns the compiled circuit, its
+// keys and one full witness per signature.
+func proveManySetup(tb testing.TB, n int) (constraint.ConstraintSystem, groth16.ProvingKey, groth16.VerifyingKey, []witness.Witness) {
+	tb.Helper()
+	pubKeys, sigs, msgs := signBatch(tb, n)
+
+	ccs, err := CompileCircuit(ecc.BN254, backend.GROTH16)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	pk, vk, err := SetupGroth16(ccs)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	witnesses := make([]witness.Witness, n)
+	for i := range witnesses {
+		assignment, err := NewAssignment(pubKeys[i], sigs[i], msgs[i], DefaultMessageLimbs)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		if witnesses[i], err = frontend.NewWitness(assignment, ccs.Field()); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	return ccs, pk, vk, witnesses
+}
+
+func TestProveMany(t *testing.T) {
+	pubKeys, sigs, _ := signBatch(t, 1)
+	ccs, pk, vk, witnesses := proveManySetup(t, 3)
+
+	// Replace the second witness with a signature over another message
+	assignment, err := NewAssignment(pubKeys[0], sigs[0], []byte{0xba, 0xd0}, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if witnesses[1], err = frontend.NewWitness(assignment, ccs.Field()); err != nil {
+		t.Fatal(err)
+	}
+
+	proofs, errs := ProveMany(ccs, pk, witnesses, 2)
+	if len(proofs) != len(witnesses) || len(errs) != len(witnesses) {
+		t.Fatalf("Expected %d proofs and errors, got %d and %d", len(witnesses), len(proofs), len(errs))
+	}
+	for i := range witnesses {
+		if i == 1 {
+			if !errors.Is(errs[i], ErrSignatureInvalid) {
+				t.Fatal("Expected ErrSignatureInvalid, got:", errs[i])
+			}
+			continue
+		}
+		if errs[i] != nil {
+			t.Fatalf("Error proving witness %d: %v", i, errs[i])
+		}
+		publicWitness, err := witnesses[i].Public()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := groth16.Verify(proofs[i], vk, publicWitness); err != nil {
+			t.Fatalf("Error verifying proof %d: %v", i, err)
+		}
+	}
+}
+
+// BenchmarkProveMany compares proving 8 statements serially with proving
+// them on a worker pool.
+func BenchmarkProveMany(b *testing.B) {
+	ccs, pk, _, witnesses := proveManySetup(b, 8)
+
+	run := func(b *testing.B, workers int) {
+		for i := 0; i < b.N; i++ {
+			_, errs := ProveMany(ccs, pk, witnesses, workers)
+			for _, err := range errs {
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	}
+	b.Run("serial", func(b *testing.B) { run(b, 1) })
+	b.Run("parallel", func(b *testing.B) { run(b, runtime.NumCPU()) })
+}
@@ -0,0 +1,369 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	csbls12377 "github.com/consensys/gnark/constraint/bls12-377"
+	csbls12381 "github.com/consensys/gnark/constraint/bls12-381"
+	csbls24315 "github.com/consensys/gnark/constraint/bls24-315"
+	csbn254 "github.com/consensys/gnark/constraint/bn254"
+	csbw6761 "github.com/consensys/gnark/constraint/bw6-761"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+)
+
+// ErrProverPanic is returned, with the recovered value, when the gnark
+// backend panics while proving or verifying, as it does on some malformed
+// keys, proofs and witnesses, so that a single bad request cannot crash a
+// server. Panics in the goroutines gnark starts itself while proving cannot
+// be recovered, so keys should still come from a trusted source.
+var ErrProverPanic = errors.New("gnark backend panicked")
+
+// ErrSignatureInvalid is returned, wrapped around the solver error, when
+// proving fails because the assignment does not satisfy the circuit, that is
+// because the signature does not verify. Any other proving error means the
+// prover itself failed.
+var ErrSignatureInvalid = errors.New("signature does not satisfy the circuit")
+
+// CompileCircuit compiles the EdDSA circuit with DefaultMessageLimbs message
+// limbs over the scalar field of curve, using the constraint system builder
+// expected by the chosen backend (R1CS for Groth16, sparse R1CS for PLONK).
+// Signatures are then verified on the twisted Edwards curve InnerCurve(curve).
+// Curves without one fail with ErrUnsupportedCurve before compiling.
+func CompileCircuit(curve ecc.ID, b backend.ID) (constraint.ConstraintSystem, error) {
+	if _, err := InnerCurve(curve); err != nil {
+		return nil, err
+	}
+	return compileCircuit(curve, b, NewEdDSACircuit(DefaultMessageLimbs))
+}
+
+// compileCircuit is CompileCircuit for an arbitrary circuit template.
+func compileCircuit(curve ecc.ID, b backend.ID, circuit frontend.Circuit) (constraint.ConstraintSystem, error) {
+	var builder frontend.NewBuilder
+	switch b {
+	case backend.GROTH16:
+		builder = r1cs.NewBuilder
+	case backend.PLONK:
+		builder = scs.NewBuilder
+	default:
+		return nil, fmt.Errorf("unsupported backend %s", b)
+	}
+
+	start := time.Now()
+	ccs, err := frontend.Compile(curve.ScalarField(), builder, circuit)
+	if err != nil {
+		return nil, fmt.Errorf("compiling circuit: %w", err)
+	}
+	logger().Info(EventCircuitCompiled, "backend", b.String(), "constraints", ccs.GetNbConstraints(), "duration", time.Since(start))
+	return ccs, nil
+}
+
+// CompileSCS compiles the EdDSA circuit over the scalar field of curve with
+// the sparse R1CS builder used by PLONK. Comparing its GetNbConstraints with
+// that of CompileCircuit(curve, backend.GROTH16) shows which backend is
+// cheaper for this circuit.
+func CompileSCS(curve ecc.ID) (constraint.ConstraintSystem, error) {
+	return CompileCircuit(curve, backend.PLONK)
+}
+
+// Analyze compiles the EdDSA circuit for Groth16 over the scalar field of
+// curve and reports its size, without running the setup or proving. The
+// public count includes the constant wire gnark adds to every circuit.
+func Analyze(curve ecc.ID) (nbConstraints, nbPublic, nbSecret int, err error) {
+	ccs, err := CompileCircuit(curve, backend.GROTH16)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return ccs.GetNbConstraints(), ccs.GetNbPublicVariables(), ccs.GetNbSecretVariables(), nil
+}
+
+// SetupGroth16 runs the Groth16 trusted setup for ccs. Its randomness comes
+// from crypto/rand, so every call yields new keys; see LoadOrSetupKeys for
+// reproducible ones.
+func SetupGroth16(ccs constraint.ConstraintSystem) (groth16.ProvingKey, groth16.VerifyingKey, error) {
+	start := time.Now()
+	pk, vk, err := groth16.Setup(ccs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("groth16 setup: %w", err)
+	}
+	logger().Info(EventSetupComplete, "backend", "groth16", "duration", time.Since(start))
+	return pk, vk, nil
+}
+
+// setupRetryDelay is the wait of SetupWithRetry after its first failed
+// attempt, doubled after each further one.
+const setupRetryDelay = time.Second
+
+// SetupWithRetry is SetupGroth16 retried up to attempts times in total, for
+// setups that fail transiently, such as under memory pressure in CI. It
+// waits one second after the first failure and twice as long after each
+// further one, and returns the error of the last attempt once all fail.
+func SetupWithRetry(ccs constraint.ConstraintSystem, attempts int) (groth16.ProvingKey, groth16.VerifyingKey, error) {
+	var pk groth16.ProvingKey
+	var vk groth16.VerifyingKey
+	err := retryWithBackoff(attempts, setupRetryDelay, func() error {
+		var err error
+		pk, vk, err = SetupGroth16(ccs)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return pk, vk, nil
+}
+
+// retryWithBackoff calls fn until it succeeds, at most attempts times,
+// waiting delay after the first failure and doubling the wait after each
+// further one. It returns the last error of fn.
+func retryWithBackoff(attempts int, delay time.Duration, fn func() error) error {
+	if attempts < 1 {
+		return fmt.Errorf("%d attempts, need at least 1", attempts)
+	}
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(); err == nil || attempt == attempts {
+			return err
+		}
+		logger().Warn(EventSetupRetry, "attempt", attempt, "delay", delay, "error", err)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// EstimateSetupMemory returns a rough estimate, in bytes, of the memory
+// SetupGroth16 needs for ccs, so that callers on constrained machines can
+// tell whether to run it. It counts the points of the proving key, which
+// holds about three G1 points and one G2 point per wire and one G1 point per
+// constraint, uncompressed, and the field elements of the domain the setup
+// evaluates its polynomials over. Allocator and garbage collector overhead
+// are not included, so the actual peak can be higher, but the estimate grows
+// with the size of the circuit.
+func EstimateSetupMemory(ccs constraint.ConstraintSystem) (uint64, error) {
+	curve, err := fieldCurve(ccs.Field())
+	if err != nil {
+		return 0, err
+	}
+	baseBytes := uint64(len(curve.BaseField().Bytes()))
+	frBytes := uint64(fieldBytes(curve))
+	g1Bytes := 2 * baseBytes
+	g2Bytes := 2 * g1Bytes
+	if curve == ecc.BLS24_315 {
+		// G2 coordinates are in a degree 4 extension instead of 2
+		g2Bytes *= 2
+	}
+
+	nbWires := uint64(ccs.GetNbPublicVariables() + ccs.GetNbSecretVariables() + ccs.GetNbInternalVariables())
+	domain := ecc.NextPowerOfTwo(uint64(ccs.GetNbConstraints()))
+	pk := 3*nbWires*g1Bytes + nbWires*g2Bytes + domain*g1Bytes
+	// The A, B and C polynomials and the wire values at every point of the
+	// domain
+	evaluations := 4 * domain * frBytes
+	return pk + evaluations, nil
+}
+
+// ProveWithGroth16 builds the full witness for assignment and proves it
+// against ccs with the Groth16 proving key pk. An unsatisfied assignment
+// fails with ErrSignatureInvalid.
+func ProveWithGroth16(ccs constraint.ConstraintSystem, pk groth16.ProvingKey, assignment frontend.Circuit) (groth16.Proof, error) {
+	if err := checkAssignmentCurve(ccs.Field(), assignment); err != nil {
+		return nil, err
+	}
+	fullWitness, err := frontend.NewWitness(assignment, ccs.Field())
+	if err != nil {
+		return nil, fmt.Errorf("creating witness: %w", err)
+	}
+
+	start := time.Now()
+	proof, err := groth16Prove(ccs, pk, fullWitness)
+	if err != nil {
+		return nil, wrapProveError("groth16", err)
+	}
+	logger().Info(EventProofGenerated, "backend", "groth16", "duration", time.Since(start))
+	return proof, nil
+}
+
+// ProveWithContext is ProveWithGroth16 returning ctx.Err() once ctx is done.
+// The context is checked before and after the witness is built, and while
+// proving. groth16.Prove itself cannot be interrupted, so a cancelled proof
+// keeps running in the background until it completes, and its result is
+// discarded.
+func ProveWithContext(ctx context.Context, ccs constraint.ConstraintSystem, pk groth16.ProvingKey, assignment frontend.Circuit) (groth16.Proof, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := checkAssignmentCurve(ccs.Field(), assignment); err != nil {
+		return nil, err
+	}
+	fullWitness, err := frontend.NewWitness(assignment, ccs.Field())
+	if err != nil {
+		return nil, fmt.Errorf("creating witness: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	type result struct {
+		proof groth16.Proof
+		err   error
+	}
+	done := make(chan result, 1)
+	start := time.Now()
+	go func() {
+		proof, err := groth16Prove(ccs, pk, fullWitness)
+		done <- result{proof, err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-done:
+		if r.err != nil {
+			return nil, wrapProveError("groth16", r.err)
+		}
+		logger().Info(EventProofGenerated, "backend", "groth16", "duration", time.Since(start))
+		return r.proof, nil
+	}
+}
+
+// wrapProveError adds ErrSignatureInvalid to err when the solver found an
+// unsatisfied constraint.
+func wrapProveError(backendName string, err error) error {
+	if isUnsatisfied(err) {
+		return fmt.Errorf("%s prove: %w: %w", backendName, ErrSignatureInvalid, err)
+	}
+	return fmt.Errorf("%s prove: %w", backendName, err)
+}
+
+// groth16Prove is groth16.Prove with panics returned as ErrProverPanic.
+func groth16Prove(ccs constraint.ConstraintSystem, pk groth16.ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (proof groth16.Proof, err error) {
+	defer recoverPanic(&err)
+	return groth16.Prove(ccs, pk, fullWitness, opts...)
+}
+
+// groth16Verify is groth16.Verify with panics returned as ErrProverPanic.
+func groth16Verify(proof groth16.Proof, vk groth16.VerifyingKey, publicWitness witness.Witness, opts ...backend.VerifierOption) (err error) {
+	defer recoverPanic(&err)
+	return groth16.Verify(proof, vk, publicWitness, opts...)
+}
+
+// recoverPanic sets *err to ErrProverPanic with the recovered value if the
+// function deferring it panicked.
+func recoverPanic(err *error) {
+	if r := recover(); r != nil {
+		*err = fmt.Errorf("%w: %v", ErrProverPanic, r)
+	}
+}
+
+// isUnsatisfied reports whether the solver error err is an unsatisfied
+// constraint, as opposed to a failure of the solver itself.
+func isUnsatisfied(err error) bool {
+	var unsatBN254 *csbn254.UnsatisfiedConstraintError
+	var unsatBLS12381 *csbls12381.UnsatisfiedConstraintError
+	var unsatBLS12377 *csbls12377.UnsatisfiedConstraintError
+	var unsatBLS24315 *csbls24315.UnsatisfiedConstraintError
+	var unsatBW6761 *csbw6761.UnsatisfiedConstraintError
+	return errors.As(err, &unsatBN254) || errors.As(err, &unsatBLS12381) || errors.As(err, &unsatBLS12377) ||
+		errors.As(err, &unsatBLS24315) || errors.As(err, &unsatBW6761)
+}
+
+// IsSatisfied compiles the EdDSA circuit matching assignment over the scalar
+// field of curve and solves it, without a setup or a proof. It returns false
+// and no error when the assignment does not satisfy the circuit, that is
+// when the signature does not verify, and an error when the check itself
+// could not run. It is much faster than proving for validating inputs.
+func IsSatisfied(curve ecc.ID, assignment *EdDSACircuit) (bool, error) {
+	if err := checkAssignmentCurve(curve.ScalarField(), assignment); err != nil {
+		return false, err
+	}
+	circuit := NewEdDSACircuit(len(assignment.Message))
+	circuit.Curve = assignment.Curve
+	circuit.RejectNeutralKey = assignment.RejectNeutralKey
+	circuit.UseCommitment = assignment.UseCommitment
+	circuit.Hash = assignment.Hash
+	circuit.MiMCKey = assignment.MiMCKey
+	circuit.NewHash = assignment.NewHash
+	ccs, err := compileCircuit(curve, backend.GROTH16, circuit)
+	if err != nil {
+		return false, err
+	}
+	fullWitness, err := frontend.NewWitness(assignment, ccs.Field())
+	if err != nil {
+		return false, fmt.Errorf("creating witness: %w", err)
+	}
+	if err := ccs.IsSolved(fullWitness); err != nil {
+		if isUnsatisfied(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("solving circuit: %w", err)
+	}
+	return true, nil
+}
+
+// VerifyWithGroth16 checks proof against the public part of assignment.
+func VerifyWithGroth16(ccs constraint.ConstraintSystem, vk groth16.VerifyingKey, proof groth16.Proof, assignment frontend.Circuit) error {
+	if err := checkAssignmentCurve(ccs.Field(), assignment); err != nil {
+		return err
+	}
+	publicWitness, err := frontend.NewWitness(assignment, ccs.Field(), frontend.PublicOnly())
+	if err != nil {
+		return fmt.Errorf("creating public witness: %w", err)
+	}
+
+	start := time.Now()
+	err = groth16Verify(proof, vk, publicWitness)
+	logVerify("groth16", start, err)
+	if err != nil {
+		return fmt.Errorf("groth16 verify: %w", err)
+	}
+	return nil
+}
+
+// VerifyProof checks a Groth16 proof for the EdDSA circuit against vk,
+// rebuilding the public witness from pubKey, sig and msg. It needs neither
+// the constraint system nor the proving key, so it is all a verifier has to
+// run.
+func VerifyProof(vk groth16.VerifyingKey, proof groth16.Proof, pubKey, sig, msg []byte) error {
+	publicWitness, err := newPublicWitness(vk.CurveID(), pubKey, sig, msg)
+	if err != nil {
+		return err
+	}
+	return verifyPublicWitness(vk, proof, publicWitness)
+}
+
+// newPublicWitness builds the public witness of the EdDSA circuit compiled
+// over curve for pubKey, sig and msg.
+func newPublicWitness(curve ecc.ID, pubKey, sig, msg []byte) (witness.Witness, error) {
+	inner, err := InnerCurve(curve)
+	if err != nil {
+		return nil, err
+	}
+	assignment, err := NewAssignmentOn(inner, pubKey, sig, msg, DefaultMessageLimbs)
+	if err != nil {
+		return nil, err
+	}
+	publicWitness, err := frontend.NewWitness(assignment, curve.ScalarField(), frontend.PublicOnly())
+	if err != nil {
+		return nil, fmt.Errorf("creating public witness: %w", err)
+	}
+	return publicWitness, nil
+}
+
+// verifyPublicWitness checks proof against vk and publicWitness.
+func verifyPublicWitness(vk groth16.VerifyingKey, proof groth16.Proof, publicWitness witness.Witness) error {
+	start := time.Now()
+	err := groth16Verify(proof, vk, publicWitness)
+	logVerify("groth16", start, err)
+	if err != nil {
+		return fmt.Errorf("groth16 verify: %w", err)
+	}
+	return nil
+}
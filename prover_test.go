@@ -0,0 +1,360 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	cryptoeddsa "github.com/consensys/gnark-crypto/signature/eddsa"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/constraint"
+)
+
+func TestVerifyProof(t *testing.T) {
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey := privateKey.Public().Bytes()
+	msg := []byte{0xde, 0xad, 0xf0, 0x0d}
+	sig, err := SignMessage(privateKey, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assignment, err := NewAssignment(pubKey, sig, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The prover side owns the constraint system and proving key
+	ccs, err := CompileCircuit(ecc.BN254, backend.GROTH16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, vk, err := SetupGroth16(ccs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := ProveWithGroth16(ccs, pk, assignment)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The verifier side only holds the verifying key and the raw inputs
+	if err := VerifyProof(vk, proof, pubKey, sig, msg); err != nil {
+		t.Fatal("Error verifying proof:", err)
+	}
+	if err := VerifyProof(vk, proof, pubKey, sig, []byte{0xba, 0xd0}); err == nil {
+		t.Fatal("Expected verification against another message to fail")
+	}
+}
+
+func TestProveInvalidSignature(t *testing.T) {
+	valid, invalid := newTestAssignments(t)
+
+	ccs, err := CompileCircuit(ecc.BN254, backend.GROTH16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, _, err := SetupGroth16(ccs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ProveWithGroth16(ccs, pk, valid); err != nil {
+		t.Fatal("Error proving:", err)
+	}
+	if _, err := ProveWithGroth16(ccs, pk, invalid); !errors.Is(err, ErrSignatureInvalid) {
+		t.Fatal("Expected ErrSignatureInvalid, got:", err)
+	}
+}
+
+func TestIsSatisfied(t *testing.T) {
+	valid, invalid := newTestAssignments(t)
+
+	if ok, err := IsSatisfied(ecc.BN254, valid); err != nil || !ok {
+		t.Fatalf("Expected the valid assignment to be satisfied, got %v, %v", ok, err)
+	}
+	// An invalid signature is a result, not an error
+	if ok, err := IsSatisfied(ecc.BN254, invalid); err != nil || ok {
+		t.Fatalf("Expected the invalid assignment to be unsatisfied, got %v, %v", ok, err)
+	}
+	if _, err := IsSatisfied(ecc.BW6_633, valid); err == nil {
+		t.Fatal("Expected an error for an unsupported curve")
+	}
+}
+
+// constraintBudget bounds the R1CS size of EdDSACircuit on BN254. The
+// baseline is 9643 constraints with MiMC and DefaultMessageLimbs limbs, of
+// which the double-base scalar multiplication is by far the largest part.
+// Raise it only after reviewing what made the circuit grow.
+const constraintBudget = 10_000
+
+func TestConstraintBudget(t *testing.T) {
+	ccs, err := CompileCircuit(ecc.BN254, backend.GROTH16)
+	if err != nil {
+		t.Fatal("Error compiling circuit:", err)
+	}
+	if n := ccs.GetNbConstraints(); n > constraintBudget {
+		t.Fatalf("Circuit has %d constraints, over the budget of %d", n, constraintBudget)
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	nbConstraints, nbPublic, nbSecret, err := Analyze(ecc.BN254)
+	if err != nil {
+		t.Fatal("Error analyzing circuit:", err)
+	}
+	if nbConstraints == 0 {
+		t.Fatal("Expected a non-empty circuit")
+	}
+	// Public key and R are two coordinates each, S is one scalar, then the
+	// message limbs and the constant wire
+	if want := 2 + 2 + 1 + DefaultMessageLimbs + 1; nbPublic != want {
+		t.Fatalf("Expected %d public variables, got %d", want, nbPublic)
+	}
+	if nbSecret != 0 {
+		t.Fatalf("Expected no secret variables, got %d", nbSecret)
+	}
+}
+
+func TestCompileSCS(t *testing.T) {
+	ccs, err := CompileSCS(ecc.BN254)
+	if err != nil {
+		t.Fatal("Error compiling circuit:", err)
+	}
+	if _, ok := ccs.(constraint.SparseR1CS); !ok {
+		t.Fatalf("Expected a sparse R1CS, got %T", ccs)
+	}
+	if ccs.GetNbConstraints() == 0 {
+		t.Fatal("Expected a non-empty circuit")
+	}
+}
+
+// BenchmarkEdDSAProve reports the size of the Groth16 circuit and times
+// proving a single signature with it.
+func BenchmarkEdDSAProve(b *testing.B) {
+	valid, _ := newTestAssignments(b)
+
+	ccs, err := CompileCircuit(ecc.BN254, backend.GROTH16)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Logf("constraints: %d, public: %d, secret: %d, internal: %d",
+		ccs.GetNbConstraints(), ccs.GetNbPublicVariables(), ccs.GetNbSecretVariables(), ccs.GetNbInternalVariables())
+
+	pk, _, err := SetupGroth16(ccs)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ProveWithGroth16(ccs, pk, valid); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(ccs.GetNbConstraints()), "constraints")
+}
+
+func TestEstimateSetupMemory(t *testing.T) {
+	ccs, err := CompileCircuit(ecc.BN254, backend.GROTH16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	estimate, err := EstimateSetupMemory(ccs)
+	if err != nil {
+		t.Fatal("Error estimating setup memory:", err)
+	}
+
+	// The estimate covers at least the proving key the setup returns
+	pk, _, err := SetupGroth16(ccs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if _, err := pk.WriteRawTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if estimate < uint64(buf.Len()) {
+		t.Fatalf("Estimated %d bytes, less than the %d bytes of the proving key", estimate, buf.Len())
+	}
+
+	batch, err := compileCircuit(ecc.BN254, backend.GROTH16, NewBatchCircuit(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	batchEstimate, err := EstimateSetupMemory(batch)
+	if err != nil {
+		t.Fatal("Error estimating setup memory:", err)
+	}
+	if batchEstimate <= estimate {
+		t.Fatalf("Estimated %d bytes for a batch of 4 signatures, not more than the %d bytes for one", batchEstimate, estimate)
+	}
+}
+
+// BenchmarkNativeVsCircuit compares verifying a signature natively, hashing
+// the message included, with proving and verifying it in the circuit, and
+// reports how many times slower the latter is.
+func BenchmarkNativeVsCircuit(b *testing.B) {
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		b.Fatal(err)
+	}
+	publicKey := privateKey.Public()
+	msg := []byte{0xde, 0xad, 0xf0, 0x0d}
+	sig, err := SignMessage(privateKey, msg, DefaultMessageLimbs)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	var native time.Duration
+	b.Run("native", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			digest, err := HashMessage(msg, DefaultMessageLimbs)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if ok, err := publicKey.Verify(sig, digest, mimc.NewMiMC()); err != nil || !ok {
+				b.Fatal("Error verifying signature:", err)
+			}
+		}
+		native = b.Elapsed() / time.Duration(b.N)
+	})
+
+	ccs, err := CompileCircuit(ecc.BN254, backend.GROTH16)
+	if err != nil {
+		b.Fatal(err)
+	}
+	pk, vk, err := SetupGroth16(ccs)
+	if err != nil {
+		b.Fatal(err)
+	}
+	assignment, err := NewAssignment(publicKey.Bytes(), sig, msg, DefaultMessageLimbs)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Run("prove+verify", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			proof, err := ProveWithGroth16(ccs, pk, assignment)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if err := VerifyWithGroth16(ccs, vk, proof, assignment); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.ReportMetric(float64(ccs.GetNbConstraints()), "constraints")
+		if native > 0 {
+			ratio := float64(b.Elapsed()/time.Duration(b.N)) / float64(native)
+			b.ReportMetric(ratio, "x-native")
+			b.Logf("%d constraints, %.0fx slower than native verification", ccs.GetNbConstraints(), ratio)
+		}
+	})
+}
+
+func TestProveWithContext(t *testing.T) {
+	valid, invalid := newTestAssignments(t)
+
+	ccs, err := CompileCircuit(ecc.BN254, backend.GROTH16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, vk, err := SetupGroth16(ccs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	proof, err := ProveWithContext(context.Background(), ccs, pk, valid)
+	if err != nil {
+		t.Fatal("Error proving:", err)
+	}
+	if err := VerifyWithGroth16(ccs, vk, proof, valid); err != nil {
+		t.Fatal("Error verifying:", err)
+	}
+	if _, err := ProveWithContext(context.Background(), ccs, pk, invalid); !errors.Is(err, ErrSignatureInvalid) {
+		t.Fatal("Expected ErrSignatureInvalid, got:", err)
+	}
+
+	// A cancelled context returns before any proving work starts
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	if _, err := ProveWithContext(ctx, ccs, pk, valid); !errors.Is(err, context.Canceled) {
+		t.Fatal("Expected context.Canceled, got:", err)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Fatalf("Expected a prompt return, took %s", elapsed)
+	}
+}
+
+func TestProverPanic(t *testing.T) {
+	valid, _ := newTestAssignments(t)
+	ccs, err := CompileCircuit(ecc.BN254, backend.GROTH16)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A proving key of another curve than the constraint system
+	if _, err := ProveWithGroth16(ccs, groth16.NewProvingKey(ecc.BLS12_381), valid); !errors.Is(err, ErrProverPanic) {
+		t.Fatal("Expected ErrProverPanic, got:", err)
+	}
+
+	// A proof checked against a verifying key of another curve
+	proof := groth16.NewProof(ecc.BN254)
+	if err := VerifyWithGroth16(ccs, groth16.NewVerifyingKey(ecc.BLS12_381), proof, valid); !errors.Is(err, ErrProverPanic) {
+		t.Fatal("Expected ErrProverPanic, got:", err)
+	}
+}
+
+func TestSetupWithRetry(t *testing.T) {
+	// A stub setup failing twice before succeeding
+	calls := 0
+	start := time.Now()
+	err := retryWithBackoff(3, time.Millisecond, func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("transient failure")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal("Expected the third attempt to succeed, got:", err)
+	}
+	if calls != 3 {
+		t.Fatalf("Expected 3 attempts, got %d", calls)
+	}
+	// 1ms after the first failure, then 2ms after the second
+	if elapsed := time.Since(start); elapsed < 3*time.Millisecond {
+		t.Fatalf("Expected a backoff of at least 3ms, took %s", elapsed)
+	}
+
+	// With only two attempts, the last error is returned
+	calls = 0
+	errTransient := errors.New("transient failure")
+	err = retryWithBackoff(2, time.Millisecond, func() error {
+		calls++
+		return errTransient
+	})
+	if !errors.Is(err, errTransient) || calls != 2 {
+		t.Fatalf("Expected the error of the second attempt, got %v after %d attempts", err, calls)
+	}
+	if err := retryWithBackoff(0, time.Millisecond, func() error { return nil }); err == nil {
+		t.Fatal("Expected an error for 0 attempts")
+	}
+
+	ccs, err := CompileCircuit(ecc.BN254, backend.GROTH16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := SetupWithRetry(ccs, 3); err != nil {
+		t.Fatal("Error running setup:", err)
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	edwardsbls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/twistededwards"
+	edwardsbls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/twistededwards"
+	edwardsbls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/twistededwards"
+	edwardsbn254 "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+)
+
+// ValidatePublicKey checks that pubBytes is the canonical compressed
+// encoding of a point on the inner twisted Edwards curve that lies in the
+// prime-order subgroup used by EdDSA. Catching this before building a
+// witness turns an opaque solver failure into a descriptive error.
+func ValidatePublicKey(inner twistededwards.ID, pubBytes []byte) error {
+	order, err := subgroupOrder(inner)
+	if err != nil {
+		return err
+	}
+	switch inner {
+	case twistededwards.BN254:
+		return validatePoint[edwardsbn254.PointAffine](pubBytes, order)
+	case twistededwards.BLS12_381:
+		return validatePoint[edwardsbls12381.PointAffine](pubBytes, order)
+	case twistededwards.BLS12_377:
+		return validatePoint[edwardsbls12377.PointAffine](pubBytes, order)
+	case twistededwards.BLS24_315:
+		return validatePoint[edwardsbls24315.PointAffine](pubBytes, order)
+	default:
+		return fmt.Errorf("unsupported twisted Edwards curve ID %d", inner)
+	}
+}
+
+// AssignPublicKeyXY sets the PublicKey of assignment to the point on inner
+// with big-endian affine coordinates x and y, for callers holding a public
+// key as coordinates rather than compressed bytes. The point must be on the
+// curve and in the prime-order subgroup, as checked by ValidatePublicKey.
+// The assignment is the same as that of the compressed key.
+func AssignPublicKeyXY(assignment *EdDSACircuit, inner twistededwards.ID, x, y []byte) error {
+	outer, err := outerCurve(inner)
+	if err != nil {
+		return err
+	}
+	if size := fieldBytes(outer); len(x) != size || len(y) != size {
+		return fmt.Errorf("public key coordinates must be %d bytes each, got %d and %d", size, len(x), len(y))
+	}
+	pubKey, err := compressPoint(inner, x, y)
+	if err != nil {
+		return fmt.Errorf("invalid public key: %w", err)
+	}
+	if err := ValidatePublicKey(inner, pubKey); err != nil {
+		return fmt.Errorf("invalid public key: %w", err)
+	}
+	assignment.Curve = inner
+	assignment.PublicKey.Assign(inner, pubKey)
+	return nil
+}
+
+// ErrNeutralPublicKey is returned for a public key that is the neutral
+// element (0, 1) of its curve. Such a key signs nothing: with A the neutral
+// element, the verification equation [S]G = R + [H]A no longer depends on
+// the message, and R = [S]G is a valid signature of anything.
+var ErrNeutralPublicKey = errors.New("public key is the neutral element of the curve")
+
+// CheckPublicKeyNotNeutral returns ErrNeutralPublicKey if pubBytes encodes
+// the neutral element of the inner twisted Edwards curve. The neutral
+// element is in the prime-order subgroup, so ValidatePublicKey accepts it.
+func CheckPublicKeyNotNeutral(inner twistededwards.ID, pubBytes []byte) error {
+	var neutral bool
+	var err error
+	switch inner {
+	case twistededwards.BN254:
+		neutral, err = isNeutralPoint[edwardsbn254.PointAffine](pubBytes)
+	case twistededwards.BLS12_381:
+		neutral, err = isNeutralPoint[edwardsbls12381.PointAffine](pubBytes)
+	case twistededwards.BLS12_377:
+		neutral, err = isNeutralPoint[edwardsbls12377.PointAffine](pubBytes)
+	case twistededwards.BLS24_315:
+		neutral, err = isNeutralPoint[edwardsbls24315.PointAffine](pubBytes)
+	case twistededwards.BLS12_381_BANDERSNATCH:
+		return ErrBandersnatchEdDSA
+	default:
+		return fmt.Errorf("unsupported twisted Edwards curve ID %d", inner)
+	}
+	if err != nil {
+		return err
+	}
+	if neutral {
+		return ErrNeutralPublicKey
+	}
+	return nil
+}
+
+// isNeutralPoint reports whether buf encodes the neutral element of the
+// curve of T.
+func isNeutralPoint[T any, P edwardsPoint[T]](buf []byte) (bool, error) {
+	p := P(new(T))
+	if len(buf) != len(p.Bytes()) {
+		return false, fmt.Errorf("public key must be %d bytes, got %d", len(p.Bytes()), len(buf))
+	}
+	if _, err := p.SetBytes(buf); err != nil {
+		return false, fmt.Errorf("decoding public key: %w", err)
+	}
+	return p.IsZero(), nil
+}
+
+// edwardsPoint is the subset of the gnark-crypto twisted Edwards point API
+// shared by the supported curves, whose compressed points are all 32 bytes.
+type edwardsPoint[T any] interface {
+	*T
+	SetBytes(buf []byte) (int, error)
+	Bytes() [32]byte
+	IsOnCurve() bool
+	IsZero() bool
+	ScalarMultiplication(p *T, scalar *big.Int) *T
+}
+
+// decodePointWith decodes buf, the compressed encoding of a point on the
+// curve of T, and returns f of the point, for callers that need more of it
+// than edwardsPoint exposes, such as its coordinates.
+func decodePointWith[T any, P edwardsPoint[T], R any](buf []byte, f func(P) R) (R, error) {
+	var zero R
+	p := P(new(T))
+	if len(buf) != len(p.Bytes()) {
+		return zero, fmt.Errorf("point must be %d bytes, got %d", len(p.Bytes()), len(buf))
+	}
+	if _, err := p.SetBytes(buf); err != nil {
+		return zero, err
+	}
+	return f(p), nil
+}
+
+// validatePoint implements ValidatePublicKey for the curve of T, whose
+// prime-order subgroup has the given order.
+func validatePoint[T any, P edwardsPoint[T]](buf []byte, order *big.Int) error {
+	p := P(new(T))
+	if len(buf) != len(p.Bytes()) {
+		return fmt.Errorf("public key must be %d bytes, got %d", len(p.Bytes()), len(buf))
+	}
+	if _, err := p.SetBytes(buf); err != nil {
+		return fmt.Errorf("decoding public key: %w", err)
+	}
+	if encoded := p.Bytes(); string(encoded[:]) != string(buf) {
+		return errors.New("public key is not canonically encoded")
+	}
+	if !p.IsOnCurve() {
+		return errors.New("public key is not on the curve")
+	}
+
+	q := P(new(T))
+	q.ScalarMultiplication((*T)(p), order)
+	if !q.IsZero() {
+		return errors.New("public key is not in the prime-order subgroup")
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	edwardsbn254 "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	eddsabn254 "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards/eddsa"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	cryptoeddsa "github.com/consensys/gnark-crypto/signature/eddsa"
+	"github.com/consensys/gnark/test"
+)
+
+func TestValidatePublicKey(t *testing.T) {
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidatePublicKey(twistededwards.BN254, privateKey.Public().Bytes()); err != nil {
+		t.Fatal("Expected a generated public key to be valid:", err)
+	}
+}
+
+func TestValidatePublicKeyGarbage(t *testing.T) {
+	tests := []struct {
+		name    string
+		pubKey  []byte
+		wantErr string
+	}{
+		{"empty", nil, "must be 32 bytes"},
+		{"short", []byte{0x01, 0x02, 0x03}, "must be 32 bytes"},
+		{"not on curve", []byte(strings.Repeat("\x01", 32)), "not on the curve"},
+		{"non canonical", []byte(strings.Repeat("\xff", 31) + "\x7f"), "not canonically encoded"},
+		{"low order", lowOrderPoint(), "not in the prime-order subgroup"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePublicKey(twistededwards.BN254, tt.pubKey)
+			if err == nil {
+				t.Fatal("Expected an error for a garbage public key")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Expected error containing %q, got %q", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestAssignPublicKeyXY(t *testing.T) {
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey := privateKey.Public().Bytes()
+	msg := []byte{0xde, 0xad, 0xf0, 0x0d}
+	sig, err := SignMessage(privateKey, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error signing message:", err)
+	}
+	want, err := NewAssignment(pubKey, sig, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+
+	var p edwardsbn254.PointAffine
+	if _, err := p.SetBytes(pubKey); err != nil {
+		t.Fatal(err)
+	}
+	x, y := p.X.Bytes(), p.Y.Bytes()
+	got := *want
+	got.PublicKey.A.X, got.PublicKey.A.Y = nil, nil
+	if err := AssignPublicKeyXY(&got, twistededwards.BN254, x[:], y[:]); err != nil {
+		t.Fatal("Error assigning public key coordinates:", err)
+	}
+	if !reflect.DeepEqual(got.PublicKey, want.PublicKey) {
+		t.Fatalf("Assigned public key %v, want %v", got.PublicKey, want.PublicKey)
+	}
+	if ok, err := IsSatisfied(ecc.BN254, &got); err != nil || !ok {
+		t.Fatal("Expected the signature to verify under the assigned key:", err)
+	}
+
+	// (1, 1) is not on the curve
+	one := make([]byte, 32)
+	one[31] = 1
+	if err := AssignPublicKeyXY(NewEdDSACircuit(DefaultMessageLimbs), twistededwards.BN254, one, one); err == nil || !strings.Contains(err.Error(), "not on the curve") {
+		t.Fatal("Expected an error for a point off the curve, got:", err)
+	}
+	if err := AssignPublicKeyXY(NewEdDSACircuit(DefaultMessageLimbs), twistededwards.BN254, x[:31], y[:]); err == nil {
+		t.Fatal("Expected an error for a short coordinate")
+	}
+}
+
+// lowOrderPoint returns the encoding of (0, -1), a point of order 2 that is
+// on the curve but outside the prime-order subgroup.
+func lowOrderPoint() []byte {
+	var p edwardsbn254.PointAffine
+	p.Y.SetOne()
+	p.Y.Neg(&p.Y)
+	b := p.Bytes()
+	return b[:]
+}
+
+func TestCheckPublicKeyNotNeutral(t *testing.T) {
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckPublicKeyNotNeutral(twistededwards.BN254, privateKey.Public().Bytes()); err != nil {
+		t.Fatal("Expected a generated public key not to be neutral:", err)
+	}
+
+	neutral, _ := neutralKeyForgery(t)
+	if err := ValidatePublicKey(twistededwards.BN254, neutral); err != nil {
+		t.Fatal("Expected the neutral element to be in the prime-order subgroup:", err)
+	}
+	if err := CheckPublicKeyNotNeutral(twistededwards.BN254, neutral); !errors.Is(err, ErrNeutralPublicKey) {
+		t.Fatal("Expected ErrNeutralPublicKey, got:", err)
+	}
+}
+
+func TestRejectNeutralKeyCircuit(t *testing.T) {
+	pubKey, sig := neutralKeyForgery(t)
+	assignment, err := NewAssignment(pubKey, sig, []byte{0xde, 0xad, 0xf0, 0x0d}, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+
+	// The forgery verifies for any message unless the neutral key is rejected
+	assert := test.NewAssert(t)
+	assert.SolvingSucceeded(NewEdDSACircuit(DefaultMessageLimbs), assignment, test.WithCurves(ecc.BN254))
+	circuit := NewEdDSACircuit(DefaultMessageLimbs)
+	circuit.RejectNeutralKey = true
+	assert.SolvingFailed(circuit, assignment, test.WithCurves(ecc.BN254))
+}
+
+// neutralKeyForgery returns the encoding of the neutral element (0, 1) as a
+// public key, and a signature R = [S]G that verifies under it for any
+// message.
+func neutralKeyForgery(tb testing.TB) (pubKey, sig []byte) {
+	tb.Helper()
+	var neutral edwardsbn254.PointAffine
+	neutral.Y.SetOne()
+	b := neutral.Bytes()
+
+	params := edwardsbn254.GetEdwardsCurve()
+	s := big.NewInt(12345)
+	var forged eddsabn254.Signature
+	forged.R.ScalarMultiplication(&params.Base, s)
+	s.FillBytes(forged.S[:])
+	return b[:], forged.Bytes()
+}
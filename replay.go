@@ -0,0 +1,95 @@
+package main
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+// replayKey identifies the statement a proof was accepted for.
+type replayKey [sha256.Size]byte
+
+// newReplayKey returns the key of the statement that sig signs msg under
+// pubKey, for circuits over curve: the hash of its public witness, the
+// field elements the proof binds. Keying on the raw inputs would let a
+// replay through under another encoding of the same witness, such as a
+// message with leading zero bytes, which has the same limbs, or a signature
+// whose R.y is written as y+p. The proof is left out too: Groth16 proofs
+// can be rerandomized by anyone, so keying on the proof bytes would let a
+// replayed authorization through under a fresh proof. The message is where
+// a protocol puts its nonce, making each authorization a distinct
+// statement.
+func newReplayKey(curve ecc.ID, pubKey, sig, msg []byte) (replayKey, error) {
+	publicWitness, err := newPublicWitness(curve, pubKey, sig, msg)
+	if err != nil {
+		return replayKey{}, err
+	}
+	b, err := publicWitness.MarshalBinary()
+	if err != nil {
+		return replayKey{}, fmt.Errorf("serializing public witness: %w", err)
+	}
+	return sha256.Sum256(b), nil
+}
+
+// replayCache remembers the keys of the last size statements accepted, so
+// that they cannot be accepted again. The least recently seen key is evicted
+// first, and an evicted statement is accepted again, so the size must cover
+// the lifetime of an authorization.
+type replayCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List // of replayKey, most recently seen first
+	entries map[replayKey]*list.Element
+}
+
+// newReplayCache returns a replayCache holding up to size keys, or nil,
+// which remembers nothing, if size is not positive.
+func newReplayCache(size int) *replayCache {
+	if size <= 0 {
+		return nil
+	}
+	return &replayCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[replayKey]*list.Element, size),
+	}
+}
+
+// seen reports whether k is in c, marking it as recently seen if so.
+func (c *replayCache) seen(k replayKey) bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[k]
+	if ok {
+		c.order.MoveToFront(e)
+	}
+	return ok
+}
+
+// add records k in c, evicting the least recently seen key if c is full. It
+// returns false if k was already there, as when the same statement is
+// accepted by two concurrent requests, of which only one may succeed.
+func (c *replayCache) add(k replayKey) bool {
+	if c == nil {
+		return true
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[k]; ok {
+		c.order.MoveToFront(e)
+		return false
+	}
+	if c.order.Len() >= c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(replayKey))
+	}
+	c.entries[k] = c.order.PushFront(k)
+	return true
+}
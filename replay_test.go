@@ -0,0 +1,28 @@
+package main
+
+import "testing"
+
+func TestReplayCache(t *testing.T) {
+	keys := []replayKey{{0}, {1}, {2}}
+
+	c := newReplayCache(2)
+	if !c.add(keys[0]) || !c.add(keys[1]) {
+		t.Fatal("Expected new keys to be added")
+	}
+	if c.add(keys[0]) {
+		t.Fatal("Expected a key already seen not to be added again")
+	}
+	// keys[0] was just seen, so adding keys[2] evicts keys[1]
+	c.add(keys[2])
+	if !c.seen(keys[0]) || !c.seen(keys[2]) {
+		t.Fatal("Expected the recently seen keys to be kept")
+	}
+	if c.seen(keys[1]) {
+		t.Fatal("Expected the least recently seen key to be evicted")
+	}
+
+	disabled := newReplayCache(0)
+	if !disabled.add(keys[0]) || !disabled.add(keys[0]) || disabled.seen(keys[0]) {
+		t.Fatal("Expected a disabled cache to remember nothing")
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// DemoReport is the result of a run of the demo, written to stdout as one
+// JSON object by -format=json so that CI can parse it.
+type DemoReport struct {
+	Curve       string      `json:"curve"`
+	Backend     string      `json:"backend"`
+	Constraints int         `json:"constraints"`
+	Timings     DemoTimings `json:"timings"`
+
+	// Valid reports whether the proof of the valid signature verified.
+	Valid bool `json:"valid"`
+
+	// TamperedRejected reports whether proving a tampered copy of the
+	// signature failed as it should. It is false when -verify skips that
+	// check.
+	TamperedRejected bool `json:"tamperedRejected"`
+
+	// Analysis holds the circuit sizes reported by -analyze, which
+	// neither proves nor verifies.
+	Analysis *DemoAnalysis `json:"analysis,omitempty"`
+
+	// Error is the error that ended the run, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// DemoAnalysis holds the size of the EdDSA circuit for both backends.
+type DemoAnalysis struct {
+	R1CSConstraints int `json:"r1csConstraints"`
+	SCSConstraints  int `json:"scsConstraints"`
+	PublicVariables int `json:"publicVariables"`
+	SecretVariables int `json:"secretVariables"`
+}
+
+// DemoTimings holds the duration of each stage of a demo run in
+// milliseconds. Stages that did not run are zero.
+type DemoTimings struct {
+	CompileMs float64 `json:"compileMs"`
+	SetupMs   float64 `json:"setupMs"`
+	ProveMs   float64 `json:"proveMs"`
+	VerifyMs  float64 `json:"verifyMs"`
+
+	// WitnessMs is only measured by -timings.
+	WitnessMs float64 `json:"witnessMs,omitempty"`
+}
+
+// newDemoTimings converts the Timings of a pipeline run.
+func newDemoTimings(t Timings) DemoTimings {
+	return DemoTimings{
+		CompileMs: milliseconds(t.Compile),
+		SetupMs:   milliseconds(t.Setup),
+		ProveMs:   milliseconds(t.Prove),
+		VerifyMs:  milliseconds(t.Verify),
+		WitnessMs: milliseconds(t.NewWitness),
+	}
+}
+
+// WriteJSON writes r to w as a single line JSON object.
+func (r DemoReport) WriteJSON(w io.Writer) error {
+	if err := json.NewEncoder(w).Encode(r); err != nil {
+		return fmt.Errorf("encoding report: %w", err)
+	}
+	return nil
+}
+
+// milliseconds returns d in milliseconds, as reported by DemoTimings.
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
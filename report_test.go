@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+)
+
+func TestDemoReportJSON(t *testing.T) {
+	ccs, err := CompileCircuit(ecc.BN254, backend.GROTH16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := DemoReport{
+		Curve:       curveName(ecc.BN254),
+		Backend:     backend.GROTH16.String(),
+		Constraints: ccs.GetNbConstraints(),
+		Timings: DemoTimings{
+			CompileMs: milliseconds(1500 * time.Microsecond),
+			SetupMs:   milliseconds(2 * time.Second),
+			ProveMs:   milliseconds(300 * time.Millisecond),
+			VerifyMs:  milliseconds(time.Millisecond),
+		},
+		Valid:            true,
+		TamperedRejected: true,
+	}
+	var buf bytes.Buffer
+	if err := report.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if n := bytes.Count(buf.Bytes(), []byte("\n")); n != 1 {
+		t.Fatalf("Expected one line of JSON, got %d: %s", n, buf.Bytes())
+	}
+
+	// CI parses the report with its own definition of the fields
+	var got struct {
+		Curve       string `json:"curve"`
+		Backend     string `json:"backend"`
+		Constraints int    `json:"constraints"`
+		Timings     struct {
+			CompileMs float64 `json:"compileMs"`
+			SetupMs   float64 `json:"setupMs"`
+			ProveMs   float64 `json:"proveMs"`
+			VerifyMs  float64 `json:"verifyMs"`
+		} `json:"timings"`
+		Valid            bool `json:"valid"`
+		TamperedRejected bool `json:"tamperedRejected"`
+	}
+	dec := json.NewDecoder(&buf)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&got); err != nil {
+		t.Fatal("Error decoding report:", err)
+	}
+	if got.Curve != "BN254" || got.Backend != "groth16" || got.Constraints != ccs.GetNbConstraints() {
+		t.Fatalf("Unexpected report %+v", got)
+	}
+	if got.Timings.CompileMs != 1.5 || got.Timings.SetupMs != 2000 || got.Timings.ProveMs != 300 || got.Timings.VerifyMs != 1 {
+		t.Fatalf("Unexpected timings %+v", got.Timings)
+	}
+	if !got.Valid || !got.TamperedRejected {
+		t.Fatalf("Unexpected outcome %+v", got)
+	}
+}
+
+func TestNewDemoTimings(t *testing.T) {
+	got := newDemoTimings(Timings{
+		Compile:    1500 * time.Microsecond,
+		Setup:      2 * time.Second,
+		NewWitness: 250 * time.Microsecond,
+		Prove:      300 * time.Millisecond,
+		Verify:     time.Millisecond,
+	})
+	want := DemoTimings{CompileMs: 1.5, SetupMs: 2000, ProveMs: 300, VerifyMs: 1, WitnessMs: 0.25}
+	if got != want {
+		t.Fatalf("Expected %+v, got %+v", want, got)
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+)
+
+// VerifyRequest is the JSON body of a /verify request. The public key,
+// signature and message are hex encoded, with or without a 0x prefix, and
+// the proof is base64 of its WriteTo encoding.
+type VerifyRequest struct {
+	PublicKey string `json:"pubKey"`
+	Signature string `json:"sig"`
+	Message   string `json:"msg"`
+	Proof     string `json:"proof"`
+}
+
+// VerifyResponse is the JSON body of a /verify response. Error explains why
+// a proof was not valid.
+type VerifyResponse struct {
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+}
+
+// ErrReplayedProof is the body of the 409 answering a request whose
+// statement was already accepted, when the replay cache is enabled.
+var ErrReplayedProof = errors.New("proof already accepted for this public key, signature and message")
+
+// ServerConfig customizes the /verify endpoint of ServeVerifyWithConfig.
+type ServerConfig struct {
+	// ReplayCacheSize, if positive, enables an in-memory cache of the last
+	// ReplayCacheSize statements whose proofs were accepted, identified by
+	// their public witness so that re-encodings of the same inputs are the
+	// same statement. A request for a statement in the cache gets a
+	// 409, whatever its proof, so that an authorization is only accepted
+	// once: the message must carry a nonce for distinct authorizations to
+	// be distinct statements. The cache is lost on restart and forgets the
+	// least recently seen statements first.
+	ReplayCacheSize int
+}
+
+// ServeVerify serves the /verify endpoint on addr, checking proofs with the
+// keys of v. It only returns if the server fails.
+func ServeVerify(addr string, v *Verifier) error {
+	return ServeVerifyWithConfig(addr, v, ServerConfig{})
+}
+
+// ServeVerifyWithConfig is ServeVerify for the endpoint described by cfg.
+func ServeVerifyWithConfig(addr string, v *Verifier, cfg ServerConfig) error {
+	return http.ListenAndServe(addr, newVerifyMux(v, cfg))
+}
+
+func newVerifyMux(v *Verifier, cfg ServerConfig) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle("/verify", verifyHandler{v, newReplayCache(cfg.ReplayCacheSize)})
+	return mux
+}
+
+// verifyHandler answers POSTed VerifyRequests. Requests that cannot be
+// decoded get a 400, and replays of an accepted statement a 409; other
+// decoded ones get a 200 whose VerifyResponse says whether the proof
+// verifies.
+type verifyHandler struct {
+	v       *Verifier
+	replays *replayCache
+}
+
+func (h verifyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req VerifyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("decoding request: %v", err), http.StatusBadRequest)
+		return
+	}
+	// The proof is read as one of the backend of the Verifier, so that
+	// PLONK verifiers are served as well as Groth16 ones
+	proof := h.v.backend.NewProof(h.v.curve)
+	pubKey, sig, msg, err := req.decode(proof)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var key replayKey
+	if h.replays != nil {
+		if key, err = newReplayKey(h.v.curve, pubKey, sig, msg); err != nil {
+			// Inputs without a public witness cannot verify either
+			writeVerifyResponse(w, VerifyResponse{Error: err.Error()})
+			return
+		}
+		if h.replays.seen(key) {
+			http.Error(w, ErrReplayedProof.Error(), http.StatusConflict)
+			return
+		}
+	}
+
+	var resp VerifyResponse
+	if err := h.v.Verify(proof, pubKey, sig, msg); err != nil {
+		resp.Error = err.Error()
+	} else if !h.replays.add(key) {
+		// Another request for the statement was accepted meanwhile
+		http.Error(w, ErrReplayedProof.Error(), http.StatusConflict)
+		return
+	} else {
+		resp.Valid = true
+	}
+	writeVerifyResponse(w, resp)
+}
+
+// writeVerifyResponse writes resp as the JSON body of a 200.
+func writeVerifyResponse(w http.ResponseWriter, resp VerifyResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	// The status line is already out, so there is nothing left to report
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// VerifyJSON reads a single VerifyRequest from r and checks its proof
+// against vk, which may come from LoadKeys or a file written by SaveKeys.
+// It returns an error only when the request cannot be read or decoded; a
+// proof that does not verify yields a VerifyResponse whose Error says why.
+func VerifyJSON(r io.Reader, vk groth16.VerifyingKey) (VerifyResponse, error) {
+	var req VerifyRequest
+	if err := json.NewDecoder(r).Decode(&req); err != nil {
+		return VerifyResponse{}, fmt.Errorf("decoding request: %w", err)
+	}
+	curve := vk.CurveID()
+	proof := groth16.NewProof(curve)
+	pubKey, sig, msg, err := req.decode(proof)
+	if err != nil {
+		return VerifyResponse{}, err
+	}
+
+	var resp VerifyResponse
+	if err := verifyRequestProof(curve, vk, proof, pubKey, sig, msg); err != nil {
+		resp.Error = err.Error()
+	} else {
+		resp.Valid = true
+	}
+	return resp, nil
+}
+
+// verifyRequestProof is VerifyProof rejecting public keys outside the
+// prime-order subgroup first, as VerifyFromFiles does.
+func verifyRequestProof(curve ecc.ID, vk groth16.VerifyingKey, proof groth16.Proof, pubKey, sig, msg []byte) error {
+	inner, err := InnerCurve(curve)
+	if err != nil {
+		return err
+	}
+	if err := ValidatePublicKey(inner, pubKey); err != nil {
+		return fmt.Errorf("invalid public key: %w", err)
+	}
+	return VerifyProof(vk, proof, pubKey, sig, msg)
+}
+
+// decode returns the raw inputs of req, reading its proof into proof. The
+// message may be empty.
+func (req VerifyRequest) decode(proof io.ReaderFrom) (pubKey, sig, msg []byte, err error) {
+	if pubKey, err = decodeHexField("pubKey", req.PublicKey); err != nil {
+		return nil, nil, nil, err
+	}
+	if sig, err = decodeHexField("sig", req.Signature); err != nil {
+		return nil, nil, nil, err
+	}
+	if msg, err = decodeHex(req.Message); err != nil {
+		return nil, nil, nil, fmt.Errorf("msg: %w", err)
+	}
+	if req.Proof == "" {
+		return nil, nil, nil, fmt.Errorf("proof: missing")
+	}
+	if err := readBase64("proof", req.Proof, proof); err != nil {
+		return nil, nil, nil, err
+	}
+	return pubKey, sig, msg, nil
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	cryptoeddsa "github.com/consensys/gnark-crypto/signature/eddsa"
+	"github.com/consensys/gnark/backend/groth16"
+)
+
+func TestServeVerify(t *testing.T) {
+	v, pubKey, sig, msg, proof := newProvedVerifier(t)
+	server := httptest.NewServer(newVerifyMux(v, ServerConfig{}))
+	defer server.Close()
+
+	post := func(body string) (int, VerifyResponse) {
+		t.Helper()
+		resp, err := http.Post(server.URL+"/verify", "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var out VerifyResponse
+		if resp.StatusCode == http.StatusOK {
+			if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+				t.Fatal("Error decoding response:", err)
+			}
+		}
+		return resp.StatusCode, out
+	}
+	request := func(msg []byte) string {
+		b, err := json.Marshal(VerifyRequest{
+			PublicKey: hex.EncodeToString(pubKey),
+			Signature: hex.EncodeToString(sig),
+			Message:   hex.EncodeToString(msg),
+			Proof:     encodeBase64(t, proof),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+
+	if status, out := post(request(msg)); status != http.StatusOK || !out.Valid {
+		t.Fatalf("Expected a valid proof, got status %d and %+v", status, out)
+	}
+	if status, out := post(request([]byte{0xba, 0xd0})); status != http.StatusOK || out.Valid {
+		t.Fatalf("Expected an invalid proof for another message, got status %d and %+v", status, out)
+	}
+
+	for name, body := range map[string]string{
+		"malformed JSON": `{"pubKey":`,
+		"bad hex":        `{"pubKey":"zz","sig":"00","msg":"","proof":""}`,
+		"missing proof":  strings.Replace(request(msg), encodeBase64(t, proof), "", 1),
+	} {
+		if status, _ := post(body); status != http.StatusBadRequest {
+			t.Fatalf("%s: expected status 400, got %d", name, status)
+		}
+	}
+
+	resp, err := http.Get(server.URL + "/verify")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Fatalf("Expected status 405 for GET, got %d", resp.StatusCode)
+	}
+}
+
+// postVerifyRequest posts req to the /verify endpoint of the server at url
+// and returns the status and, for a 200, the decoded response.
+func postVerifyRequest(tb testing.TB, url string, req VerifyRequest) (int, VerifyResponse) {
+	tb.Helper()
+	body, err := json.Marshal(req)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	resp, err := http.Post(url+"/verify", "application/json", strings.NewReader(string(body)))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var out VerifyResponse
+	if resp.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+			tb.Fatal("Error decoding response:", err)
+		}
+	}
+	return resp.StatusCode, out
+}
+
+func TestServeVerifyPlonk(t *testing.T) {
+	v, err := NewVerifierWithConfig(ecc.BN254, VerifierConfig{Backend: PlonkBackend{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte{0xde, 0xad, 0xf0, 0x0d}
+	proof, sig, err := SignAndProve(privateKey, msg, v)
+	if err != nil {
+		t.Fatal("Error proving:", err)
+	}
+	server := httptest.NewServer(newVerifyMux(v, ServerConfig{}))
+	defer server.Close()
+
+	status, out := postVerifyRequest(t, server.URL, VerifyRequest{
+		PublicKey: hex.EncodeToString(privateKey.Public().Bytes()),
+		Signature: hex.EncodeToString(sig),
+		Message:   hex.EncodeToString(msg),
+		Proof:     encodeBase64(t, proof),
+	})
+	if status != http.StatusOK || !out.Valid {
+		t.Fatalf("Expected a valid PLONK proof, got status %d and %+v", status, out)
+	}
+}
+
+func TestServeVerifyReplay(t *testing.T) {
+	v, pubKey, sig, msg, proof := newProvedVerifier(t)
+	server := httptest.NewServer(newVerifyMux(v, ServerConfig{ReplayCacheSize: 4}))
+	defer server.Close()
+	uncached := httptest.NewServer(newVerifyMux(v, ServerConfig{}))
+	defer uncached.Close()
+
+	request := func(sig, msg []byte) VerifyRequest {
+		return VerifyRequest{
+			PublicKey: hex.EncodeToString(pubKey),
+			Signature: hex.EncodeToString(sig),
+			Message:   hex.EncodeToString(msg),
+			Proof:     encodeBase64(t, proof),
+		}
+	}
+
+	// Rejected proofs are not remembered, so they are answered each time
+	for range 2 {
+		if status, out := postVerifyRequest(t, server.URL, request(sig, []byte("another nonce"))); status != http.StatusOK || out.Valid {
+			t.Fatalf("Expected an invalid proof, got status %d and %+v", status, out)
+		}
+	}
+	if status, out := postVerifyRequest(t, server.URL, request(sig, msg)); status != http.StatusOK || !out.Valid {
+		t.Fatalf("Expected the first submission to be valid, got status %d and %+v", status, out)
+	}
+	if status, _ := postVerifyRequest(t, server.URL, request(sig, msg)); status != http.StatusConflict {
+		t.Fatalf("Expected status 409 for the replayed submission, got %d", status)
+	}
+
+	// Re-encodings of the same public witness are valid for the proof, so
+	// they must be recognized as replays too
+	for name, req := range map[string]VerifyRequest{
+		"message with a leading zero byte": request(sig, append([]byte{0}, msg...)),
+		"R.y written as y+p":               request(addModulusToRY(t, sig), msg),
+	} {
+		if status, out := postVerifyRequest(t, uncached.URL, req); status != http.StatusOK || !out.Valid {
+			t.Fatalf("%s: expected the proof to verify without a replay cache, got status %d and %+v", name, status, out)
+		}
+		if status, _ := postVerifyRequest(t, server.URL, req); status != http.StatusConflict {
+			t.Fatalf("%s: expected status 409 for the replay, got %d", name, status)
+		}
+	}
+}
+
+// addModulusToRY returns sig, a BN254 signature, with the Y coordinate of R
+// encoded as y+p, which decodes to the same point.
+func addModulusToRY(tb testing.TB, sig []byte) []byte {
+	tb.Helper()
+	const size = 32
+	sign := sig[size-1] & 0x80
+	be := slices.Clone(sig[:size])
+	slices.Reverse(be)
+	be[0] &^= 0x80
+	y := new(big.Int).SetBytes(be)
+	y.Add(y, ecc.BN254.ScalarField())
+	if y.BitLen() >= 8*size {
+		tb.Fatal("y+p does not fit in the encoding")
+	}
+	r := y.FillBytes(make([]byte, size))
+	slices.Reverse(r)
+	r[size-1] |= sign
+	return append(r, sig[size:]...)
+}
+
+func TestVerifyJSON(t *testing.T) {
+	v, pubKey, sig, msg, proof := newProvedVerifier(t)
+
+	// Feed the request through a pipe, as the verify subcommand reads it
+	// from stdin
+	verify := func(req VerifyRequest) (VerifyResponse, error) {
+		t.Helper()
+		r, w := io.Pipe()
+		go func() {
+			w.CloseWithError(json.NewEncoder(w).Encode(req))
+		}()
+		return VerifyJSON(r, v.keys.Load().vk.(groth16.VerifyingKey))
+	}
+	req := VerifyRequest{
+		PublicKey: hex.EncodeToString(pubKey),
+		Signature: hex.EncodeToString(sig),
+		Message:   hex.EncodeToString(msg),
+		Proof:     encodeBase64(t, proof),
+	}
+
+	if resp, err := verify(req); err != nil || !resp.Valid {
+		t.Fatalf("Expected a valid proof, got %+v and error %v", resp, err)
+	}
+	tampered := req
+	tampered.Message = "bad0"
+	if resp, err := verify(tampered); err != nil || resp.Valid || resp.Error == "" {
+		t.Fatalf("Expected an invalid proof for another message, got %+v and error %v", resp, err)
+	}
+	missing := req
+	missing.Proof = ""
+	if _, err := verify(missing); err == nil {
+		t.Fatal("Expected an error for a request without a proof")
+	}
+	if _, err := VerifyJSON(strings.NewReader(`{"pubKey":`), v.keys.Load().vk.(groth16.VerifyingKey)); err == nil {
+		t.Fatal("Expected an error for malformed JSON")
+	}
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	frbls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	edwardsbls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/twistededwards"
+	eddsabls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/twistededwards/eddsa"
+	frbls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	edwardsbls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/twistededwards"
+	eddsabls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/twistededwards/eddsa"
+	frbls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	edwardsbls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/twistededwards"
+	eddsabls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/twistededwards/eddsa"
+	frbn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	edwardsbn254 "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	eddsabn254 "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards/eddsa"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+)
+
+// ParseExternalSignature assembles a signature produced by another EdDSA
+// implementation from its R and S components into the encoding that
+// NewAssignmentOn and eddsa.Signature.Assign expect for the curve inner.
+//
+// With n the size of a scalar field element of the outer curve (32 bytes for
+// every supported curve), that encoding is 2n bytes:
+//
+//   - R compressed as in RFC 8032: the n-byte little-endian Y coordinate,
+//     whose most significant bit is set if X is lexicographically the
+//     largest of X and -X;
+//   - S as an n-byte big-endian integer below the subgroup order.
+//
+// r may be given either compressed as above, or uncompressed as the
+// big-endian X and Y coordinates, 2n bytes. s may be shorter than n bytes
+// and is then left-padded with zeros. The result is checked to be a valid
+// signature encoding, but not verified against any key.
+func ParseExternalSignature(inner twistededwards.ID, r, s []byte) ([]byte, error) {
+	outer, err := outerCurve(inner)
+	if err != nil {
+		return nil, err
+	}
+	size := fieldBytes(outer)
+
+	var sig []byte
+	switch len(r) {
+	case size:
+		sig = append(sig, r...)
+	case 2 * size:
+		compressed, err := compressPoint(inner, r[:size], r[size:])
+		if err != nil {
+			return nil, fmt.Errorf("R: %w", err)
+		}
+		sig = append(sig, compressed...)
+	default:
+		return nil, fmt.Errorf("R must be %d bytes compressed or %d bytes uncompressed, got %d", size, 2*size, len(r))
+	}
+	if len(s) > size {
+		return nil, fmt.Errorf("S must be at most %d bytes, got %d", size, len(s))
+	}
+	sig = append(sig, make([]byte, size-len(s))...)
+	sig = append(sig, s...)
+
+	if err := checkSignature(inner, sig); err != nil {
+		return nil, fmt.Errorf("invalid signature: %w", err)
+	}
+	return sig, nil
+}
+
+// IsCanonicalSignature reports whether sig is a signature encoding for the
+// curve inner whose S component is below the order of the prime-order
+// subgroup. The circuit reduces S modulo the order, so S and S plus the
+// order would otherwise both be accepted for the same R.
+func IsCanonicalSignature(inner twistededwards.ID, sig []byte) bool {
+	outer, err := outerCurve(inner)
+	if err != nil {
+		return false
+	}
+	size := fieldBytes(outer)
+	if len(sig) != 2*size {
+		return false
+	}
+	order, err := subgroupOrder(inner)
+	if err != nil {
+		return false
+	}
+	return new(big.Int).SetBytes(sig[size:]).Cmp(order) < 0
+}
+
+// subgroupOrder returns the order of the prime-order subgroup of inner.
+func subgroupOrder(inner twistededwards.ID) (*big.Int, error) {
+	switch inner {
+	case twistededwards.BN254:
+		params := edwardsbn254.GetEdwardsCurve()
+		return &params.Order, nil
+	case twistededwards.BLS12_381:
+		params := edwardsbls12381.GetEdwardsCurve()
+		return &params.Order, nil
+	case twistededwards.BLS12_377:
+		params := edwardsbls12377.GetEdwardsCurve()
+		return &params.Order, nil
+	case twistededwards.BLS24_315:
+		params := edwardsbls24315.GetEdwardsCurve()
+		return &params.Order, nil
+	case twistededwards.BLS12_381_BANDERSNATCH:
+		return nil, ErrBandersnatchEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported twisted Edwards curve ID %d", inner)
+	}
+}
+
+// compressPoint returns the compressed encoding of the point with big-endian
+// coordinates x and y on inner. x and y must each be fieldBytes long.
+func compressPoint(inner twistededwards.ID, x, y []byte) ([]byte, error) {
+	switch inner {
+	case twistededwards.BN254:
+		var p edwardsbn254.PointAffine
+		var err error
+		if p.X, err = frbn254.BigEndian.Element((*[frbn254.Bytes]byte)(x)); err != nil {
+			return nil, fmt.Errorf("X: %w", err)
+		}
+		if p.Y, err = frbn254.BigEndian.Element((*[frbn254.Bytes]byte)(y)); err != nil {
+			return nil, fmt.Errorf("Y: %w", err)
+		}
+		if !p.IsOnCurve() {
+			return nil, errors.New("point is not on the curve")
+		}
+		b := p.Bytes()
+		return b[:], nil
+	case twistededwards.BLS12_381:
+		var p edwardsbls12381.PointAffine
+		var err error
+		if p.X, err = frbls12381.BigEndian.Element((*[frbls12381.Bytes]byte)(x)); err != nil {
+			return nil, fmt.Errorf("X: %w", err)
+		}
+		if p.Y, err = frbls12381.BigEndian.Element((*[frbls12381.Bytes]byte)(y)); err != nil {
+			return nil, fmt.Errorf("Y: %w", err)
+		}
+		if !p.IsOnCurve() {
+			return nil, errors.New("point is not on the curve")
+		}
+		b := p.Bytes()
+		return b[:], nil
+	case twistededwards.BLS12_377:
+		var p edwardsbls12377.PointAffine
+		var err error
+		if p.X, err = frbls12377.BigEndian.Element((*[frbls12377.Bytes]byte)(x)); err != nil {
+			return nil, fmt.Errorf("X: %w", err)
+		}
+		if p.Y, err = frbls12377.BigEndian.Element((*[frbls12377.Bytes]byte)(y)); err != nil {
+			return nil, fmt.Errorf("Y: %w", err)
+		}
+		if !p.IsOnCurve() {
+			return nil, errors.New("point is not on the curve")
+		}
+		b := p.Bytes()
+		return b[:], nil
+	case twistededwards.BLS24_315:
+		var p edwardsbls24315.PointAffine
+		var err error
+		if p.X, err = frbls24315.BigEndian.Element((*[frbls24315.Bytes]byte)(x)); err != nil {
+			return nil, fmt.Errorf("X: %w", err)
+		}
+		if p.Y, err = frbls24315.BigEndian.Element((*[frbls24315.Bytes]byte)(y)); err != nil {
+			return nil, fmt.Errorf("Y: %w", err)
+		}
+		if !p.IsOnCurve() {
+			return nil, errors.New("point is not on the curve")
+		}
+		b := p.Bytes()
+		return b[:], nil
+	default:
+		return nil, fmt.Errorf("unsupported twisted Edwards curve ID %d", inner)
+	}
+}
+
+// checkSignature decodes sig with gnark-crypto, which rejects points off the
+// curve and non-canonical components.
+func checkSignature(inner twistededwards.ID, sig []byte) error {
+	var err error
+	switch inner {
+	case twistededwards.BN254:
+		_, err = new(eddsabn254.Signature).SetBytes(sig)
+	case twistededwards.BLS12_381:
+		_, err = new(eddsabls12381.Signature).SetBytes(sig)
+	case twistededwards.BLS12_377:
+		_, err = new(eddsabls12377.Signature).SetBytes(sig)
+	case twistededwards.BLS24_315:
+		_, err = new(eddsabls24315.Signature).SetBytes(sig)
+	default:
+		err = fmt.Errorf("unsupported twisted Edwards curve ID %d", inner)
+	}
+	return err
+}
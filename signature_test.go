@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	edwardsbn254 "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	cryptoeddsa "github.com/consensys/gnark-crypto/signature/eddsa"
+)
+
+func TestParseExternalSignature(t *testing.T) {
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal("Error creating private key:", err)
+	}
+	msg := []byte{0xde, 0xad, 0xf0, 0x0d}
+	sig, err := SignMessage(privateKey, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error signing message:", err)
+	}
+
+	// Split the known-good signature the way an external signer would
+	// output it: R as affine coordinates, S as a minimal big-endian integer
+	var r edwardsbn254.PointAffine
+	if _, err := r.SetBytes(sig[:32]); err != nil {
+		t.Fatal(err)
+	}
+	x, y := r.X.Bytes(), r.Y.Bytes()
+	uncompressed := append(x[:], y[:]...)
+	s := new(big.Int).SetBytes(sig[32:]).Bytes()
+
+	for name, rBytes := range map[string][]byte{"compressed": sig[:32], "uncompressed": uncompressed} {
+		got, err := ParseExternalSignature(twistededwards.BN254, rBytes, s)
+		if err != nil {
+			t.Fatalf("Error parsing %s signature: %v", name, err)
+		}
+		if !bytes.Equal(got, sig) {
+			t.Fatalf("Parsing %s signature: expected %x, got %x", name, sig, got)
+		}
+	}
+
+	// A point off the curve is rejected
+	uncompressed[len(uncompressed)-1] ^= 0x01
+	if _, err := ParseExternalSignature(twistededwards.BN254, uncompressed, s); err == nil {
+		t.Fatal("Expected an error for R off the curve")
+	}
+}
+
+func TestIsCanonicalSignature(t *testing.T) {
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal("Error creating private key:", err)
+	}
+	sig, err := SignMessage(privateKey, []byte{0xde, 0xad, 0xf0, 0x0d}, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error signing message:", err)
+	}
+	if !IsCanonicalSignature(twistededwards.BN254, sig) {
+		t.Fatal("Expected a freshly made signature to be canonical")
+	}
+
+	// S plus the order still fits in 32 bytes, and is rejected
+	params := edwardsbn254.GetEdwardsCurve()
+	s := new(big.Int).SetBytes(sig[32:])
+	s.Add(s, &params.Order)
+	malleable := append([]byte(nil), sig[:32]...)
+	malleable = append(malleable, s.FillBytes(make([]byte, 32))...)
+	if IsCanonicalSignature(twistededwards.BN254, malleable) {
+		t.Fatal("Expected S increased by the order to be non-canonical")
+	}
+
+	if IsCanonicalSignature(twistededwards.BN254, sig[:32]) {
+		t.Fatal("Expected a truncated signature to be non-canonical")
+	}
+}
+
+func FuzzParseSignature(f *testing.F) {
+	privateKey, err := NewKeyFromSeed(twistededwards.BN254, []byte("fuzz"))
+	if err != nil {
+		f.Fatal("Error creating private key:", err)
+	}
+	pubKey := privateKey.Public().Bytes()
+	msg := []byte{0xde, 0xad, 0xf0, 0x0d}
+	sig, err := SignMessage(privateKey, msg, DefaultMessageLimbs)
+	if err != nil {
+		f.Fatal("Error signing message:", err)
+	}
+	f.Add(sig[:32], sig[32:])
+
+	f.Fuzz(func(t *testing.T, r, s []byte) {
+		// Building an assignment from the raw concatenation must fail
+		// cleanly or assign every component
+		raw := append(append([]byte(nil), r...), s...)
+		if assignment, err := NewAssignmentOn(twistededwards.BN254, pubKey, raw, msg, DefaultMessageLimbs); err == nil {
+			if assignment.Signature.R.X == nil || assignment.Signature.R.Y == nil || assignment.Signature.S == nil {
+				t.Fatalf("Incomplete assignment for signature %x", raw)
+			}
+		}
+
+		// Whatever ParseExternalSignature accepts, NewAssignmentOn must too
+		parsed, err := ParseExternalSignature(twistededwards.BN254, r, s)
+		if err != nil {
+			return
+		}
+		if _, err := NewAssignmentOn(twistededwards.BN254, pubKey, parsed, msg, DefaultMessageLimbs); err != nil {
+			t.Fatalf("Parsed signature %x rejected by NewAssignmentOn: %v", parsed, err)
+		}
+	})
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+)
+
+// ExportSolidityVerifier writes a Solidity contract verifying Groth16 proofs
+// for vk to w. gnark only generates contracts for BN254 keys, the curve with
+// pairing precompiles on Ethereum.
+func ExportSolidityVerifier(vk groth16.VerifyingKey, w io.Writer) error {
+	if vk.CurveID() != ecc.BN254 {
+		return fmt.Errorf("solidity verifiers require a BN254 verifying key, got %s", vk.CurveID())
+	}
+	if err := vk.ExportSolidity(w); err != nil {
+		return fmt.Errorf("exporting solidity verifier: %w", err)
+	}
+	return nil
+}
+
+// exportSolidityFile writes the Solidity verifier for vk to path.
+func exportSolidityFile(vk groth16.VerifyingKey, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := ExportSolidityVerifier(vk, f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
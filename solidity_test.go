@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+)
+
+func TestExportSolidityVerifier(t *testing.T) {
+	ccs, err := CompileCircuit(ecc.BN254, backend.GROTH16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, vk, err := SetupGroth16(ccs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := ExportSolidityVerifier(vk, &buf); err != nil {
+		t.Fatal("Error exporting solidity verifier:", err)
+	}
+	if buf.Len() == 0 {
+		t.Fatal("Expected a non-empty solidity verifier")
+	}
+	if !strings.Contains(buf.String(), "contract Verifier") {
+		t.Fatal("Expected the output to declare contract Verifier")
+	}
+}
+
+func TestExportSolidityVerifierUnsupportedCurve(t *testing.T) {
+	vk := groth16.NewVerifyingKey(ecc.BLS12_381)
+
+	var buf bytes.Buffer
+	if err := ExportSolidityVerifier(vk, &buf); err == nil {
+		t.Fatal("Expected an error for a BLS12-381 verifying key")
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark-crypto/signature"
+	"github.com/consensys/gnark/frontend"
+	tedwards "github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/rangecheck"
+	"github.com/consensys/gnark/std/signature/eddsa"
+)
+
+// TimestampBits is the width of the timestamps TimestampEdDSACircuit
+// accepts.
+const TimestampBits = 64
+
+// timestampDomain is the domain whose DomainTag TimestampEdDSACircuit
+// absorbs first, so that its signatures verify in no other circuit with the
+// same layout of field elements, such as AmountEdDSACircuit.
+const timestampDomain = "eddsa-gnark timestamp"
+
+// TimestampEdDSACircuit verifies a signature over H(tag || timestamp ||
+// message), the MiMC digest of the tag of timestampDomain, Timestamp and
+// the Message limbs, and checks that the signed Timestamp is fresh:
+// CurrentTime-MaxAge <= Timestamp <= CurrentTime. Timestamps and CurrentTime
+// are in the same unit as MaxAge, such as Unix seconds, and must fit in
+// TimestampBits bits.
+type TimestampEdDSACircuit struct {
+	PublicKey   eddsa.PublicKey     `gnark:",public"`
+	Signature   eddsa.Signature     `gnark:",public"`
+	Timestamp   frontend.Variable   `gnark:",public"`
+	CurrentTime frontend.Variable   `gnark:",public"`
+	Message     []frontend.Variable `gnark:",public"`
+
+	// MaxAge is the age beyond which a timestamp is stale. It is fixed at
+	// compile time.
+	MaxAge uint64 `gnark:"-"`
+
+	// UseCommitment verifies the signature with table lookups checked
+	// against a commitment to the witness. See EdDSACircuit.UseCommitment.
+	UseCommitment bool `gnark:"-"`
+}
+
+// NewTimestampCircuit returns a timestamp circuit accepting timestamps at
+// most maxAge old, whose Message holds nbLimbs limbs.
+func NewTimestampCircuit(maxAge uint64, nbLimbs int) *TimestampEdDSACircuit {
+	return &TimestampEdDSACircuit{MaxAge: maxAge, Message: make([]frontend.Variable, nbLimbs)}
+}
+
+// HashTimestampMessage returns the MiMC digest of the timestamp domain tag,
+// timestamp and msg split into nbLimbs limbs, matching
+// TimestampEdDSACircuit.
+func HashTimestampMessage(timestamp uint64, msg []byte, nbLimbs int) ([]byte, error) {
+	tag, err := DomainTag(timestampDomain)
+	if err != nil {
+		return nil, err
+	}
+	elements, err := messageElements(msg, nbLimbs)
+	if err != nil {
+		return nil, err
+	}
+	elements = append([]*big.Int{tag.BigInt(new(big.Int)), new(big.Int).SetUint64(timestamp)}, elements...)
+	return hashElements(mimc.NewMiMC(), ecc.BN254, elements)
+}
+
+// SignTimestampMessage signs the digest computed by HashTimestampMessage
+// with a BN254 private key.
+func SignTimestampMessage(priv signature.Signer, timestamp uint64, msg []byte, nbLimbs int) ([]byte, error) {
+	digest, err := HashTimestampMessage(timestamp, msg, nbLimbs)
+	if err != nil {
+		return nil, err
+	}
+	return priv.Sign(digest, mimc.NewMiMC())
+}
+
+// NewTimestampAssignment returns an assignment of the timestamp circuit with
+// nbLimbs message limbs for a BN254 public key, a signature produced by
+// SignTimestampMessage and the verifier's current time.
+func NewTimestampAssignment(pubKey, sig []byte, timestamp, currentTime uint64, msg []byte, nbLimbs int) (*TimestampEdDSACircuit, error) {
+	limbs, err := MessageLimbs(msg, nbLimbs)
+	if err != nil {
+		return nil, err
+	}
+	assignment := &TimestampEdDSACircuit{Timestamp: timestamp, CurrentTime: currentTime, Message: limbs}
+	if err := assignEncodings(twistededwards.BN254, &assignment.PublicKey, &assignment.Signature, pubKey, sig); err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
+
+// Define implements the circuit for EdDSA verification of a message carrying
+// a fresh timestamp
+func (circuit *TimestampEdDSACircuit) Define(api frontend.API) error {
+	curve, err := tedwards.NewEdCurve(api, twistededwards.BN254)
+	if err != nil {
+		return err
+	}
+	curve = commitCurve(curve, circuit.UseCommitment)
+	hash, err := newMiMCFieldHasher(api)
+	if err != nil {
+		return err
+	}
+	tag, err := DomainTag(timestampDomain)
+	if err != nil {
+		return err
+	}
+
+	// With both times below 2^TimestampBits, the age CurrentTime-Timestamp
+	// only fits in TimestampBits bits if Timestamp <= CurrentTime, since a
+	// timestamp in the future wraps around the field. Likewise, MaxAge-age
+	// only fits if the age is at most MaxAge.
+	checker := rangecheck.New(api)
+	checker.Check(circuit.Timestamp, TimestampBits)
+	checker.Check(circuit.CurrentTime, TimestampBits)
+	age := api.Sub(circuit.CurrentTime, circuit.Timestamp)
+	checker.Check(age, TimestampBits)
+	checker.Check(api.Sub(circuit.MaxAge, age), TimestampBits)
+
+	// The domain tag and the timestamp are absorbed before the message limbs
+	fields := append([]frontend.Variable{tag, circuit.Timestamp}, circuit.Message...)
+	return verifyMessageSignature(curve, hash, circuit.PublicKey, circuit.Signature, fields)
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	cryptoeddsa "github.com/consensys/gnark-crypto/signature/eddsa"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/test"
+)
+
+func TestTimestampEdDSACircuit(t *testing.T) {
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal("Error creating private key:", err)
+	}
+	pubKey := privateKey.Public().Bytes()
+	msg := []byte{0xde, 0xad, 0xf0, 0x0d}
+
+	const (
+		maxAge = 300
+		now    = 1_700_000_000
+	)
+	assert := test.NewAssert(t)
+	for _, tc := range []struct {
+		name      string
+		timestamp uint64
+		fresh     bool
+	}{
+		{"fresh", now - 10, true},
+		{"now", now, true},
+		{"oldest fresh", now - maxAge, true},
+		{"stale", now - maxAge - 1, false},
+		{"future", now + 1, false},
+	} {
+		sig, err := SignTimestampMessage(privateKey, tc.timestamp, msg, DefaultMessageLimbs)
+		if err != nil {
+			t.Fatal("Error signing message:", err)
+		}
+		assignment, err := NewTimestampAssignment(pubKey, sig, tc.timestamp, now, msg, DefaultMessageLimbs)
+		if err != nil {
+			t.Fatal("Error creating assignment:", err)
+		}
+		circuit := NewTimestampCircuit(maxAge, DefaultMessageLimbs)
+		if tc.fresh {
+			assert.SolvingSucceeded(circuit, assignment, test.WithCurves(ecc.BN254))
+		} else {
+			assert.SolvingFailed(circuit, assignment, test.WithCurves(ecc.BN254))
+		}
+	}
+
+	// A fresh timestamp other than the signed one fails
+	sig, err := SignTimestampMessage(privateKey, now-10, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error signing message:", err)
+	}
+	assignment, err := NewTimestampAssignment(pubKey, sig, now-20, now, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+	assert.SolvingFailed(NewTimestampCircuit(maxAge, DefaultMessageLimbs), assignment, test.WithCurves(ecc.BN254))
+}
+
+func TestTimestampAmountDomainSeparation(t *testing.T) {
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal("Error creating private key:", err)
+	}
+	pubKey := privateKey.Public().Bytes()
+	msg := []byte{0xde, 0xad, 0xf0, 0x0d}
+	const now = 1_700_000_000
+
+	// The same value and message signed for one circuit fails in the other
+	amountSig, err := SignAmountMessage(privateKey, new(big.Int).SetUint64(now), msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error signing message:", err)
+	}
+	timestampAssignment, err := NewTimestampAssignment(pubKey, amountSig, now, now, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+	assert := test.NewAssert(t)
+	assert.SolvingFailed(NewTimestampCircuit(300, DefaultMessageLimbs), timestampAssignment, test.WithCurves(ecc.BN254))
+
+	timestampSig, err := SignTimestampMessage(privateKey, now, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error signing message:", err)
+	}
+	amountAssignment, err := NewAmountAssignment(pubKey, timestampSig, new(big.Int).SetUint64(now), msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+	assert.SolvingFailed(NewAmountCircuit(DefaultMessageLimbs), amountAssignment, test.WithCurves(ecc.BN254))
+}
+
+func TestTimestampEdDSACircuitCommitment(t *testing.T) {
+	const (
+		maxAge = 300
+		now    = 1_700_000_000
+	)
+	plain := NewTimestampCircuit(maxAge, DefaultMessageLimbs)
+	plainCCS, err := compileCircuit(ecc.BN254, backend.GROTH16, plain)
+	if err != nil {
+		t.Fatal("Error compiling circuit:", err)
+	}
+	committed := NewTimestampCircuit(maxAge, DefaultMessageLimbs)
+	committed.UseCommitment = true
+	committedCCS, err := compileCircuit(ecc.BN254, backend.GROTH16, committed)
+	if err != nil {
+		t.Fatal("Error compiling circuit:", err)
+	}
+	t.Logf("constraints: %d without a commitment, %d with one", plainCCS.GetNbConstraints(), committedCCS.GetNbConstraints())
+	if committedCCS.GetNbConstraints() >= plainCCS.GetNbConstraints() {
+		t.Fatal("Expected the commitment to reduce the constraint count")
+	}
+
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal("Error creating private key:", err)
+	}
+	pubKey := privateKey.Public().Bytes()
+	msg := []byte{0xde, 0xad, 0xf0, 0x0d}
+	sig, err := SignTimestampMessage(privateKey, now-10, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error signing message:", err)
+	}
+	assignment, err := NewTimestampAssignment(pubKey, sig, now-10, now, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+	assertProves(t, committed, assignment)
+
+	// A tampered signature and a stale timestamp both fail to prove
+	pk, _, err := SetupGroth16(committedCCS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherSig, err := SignTimestampMessage(privateKey, now-20, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error signing message:", err)
+	}
+	tampered, err := NewTimestampAssignment(pubKey, otherSig, now-10, now, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+	if _, err := ProveWithGroth16(committedCCS, pk, tampered); !errors.Is(err, ErrSignatureInvalid) {
+		t.Fatal("Expected ErrSignatureInvalid for a tampered signature, got:", err)
+	}
+	stale, err := NewTimestampAssignment(pubKey, sig, now-10, now+maxAge, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+	if _, err := ProveWithGroth16(committedCCS, pk, stale); !errors.Is(err, ErrSignatureInvalid) {
+		t.Fatal("Expected ErrSignatureInvalid for a stale timestamp, got:", err)
+	}
+}
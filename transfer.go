@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark-crypto/signature"
+	"github.com/consensys/gnark/frontend"
+	tedwards "github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/signature/eddsa"
+)
+
+// TransferFields holds the native values of an account-state transition.
+// Sender and Receiver identify accounts, for instance by their index in an
+// account tree, and must be BN254 scalar field elements.
+type TransferFields struct {
+	Sender   *big.Int
+	Receiver *big.Int
+	Amount   uint64
+	Nonce    uint64
+}
+
+// transferDomain is the domain whose DomainTag TransferCircuit absorbs
+// first, so that its signatures verify in no other circuit with the same
+// layout of field elements, such as PaymentEdDSACircuit.
+const transferDomain = "eddsa-gnark transfer"
+
+// TransferCircuit verifies a signature over H(tag || sender || receiver ||
+// amount || nonce), the MiMC digest of the tag of transferDomain and a
+// transfer between two accounts, as a rollup does before applying it.
+// Every field is public so that the verifier can update the account
+// states; dropping the public tag of a field hides it while the signature
+// still binds it.
+type TransferCircuit struct {
+	PublicKey eddsa.PublicKey   `gnark:",public"`
+	Signature eddsa.Signature   `gnark:",public"`
+	Sender    frontend.Variable `gnark:",public"`
+	Receiver  frontend.Variable `gnark:",public"`
+	Amount    frontend.Variable `gnark:",public"`
+	Nonce     frontend.Variable `gnark:",public"`
+}
+
+// HashTransfer returns the MiMC digest of the transfer domain tag and the
+// Sender, Receiver, Amount and Nonce of fields, matching TransferCircuit.
+func HashTransfer(fields TransferFields) ([]byte, error) {
+	tag, err := DomainTag(transferDomain)
+	if err != nil {
+		return nil, err
+	}
+	elements, err := fields.elements()
+	if err != nil {
+		return nil, err
+	}
+	elements = append([]*big.Int{tag.BigInt(new(big.Int))}, elements...)
+	return hashElements(mimc.NewMiMC(), ecc.BN254, elements)
+}
+
+// SignTransfer signs the digest computed by HashTransfer with a BN254
+// private key.
+func SignTransfer(priv signature.Signer, fields TransferFields) ([]byte, error) {
+	digest, err := HashTransfer(fields)
+	if err != nil {
+		return nil, err
+	}
+	return priv.Sign(digest, mimc.NewMiMC())
+}
+
+// NewTransferAssignment returns an assignment of TransferCircuit for a BN254
+// public key and a signature produced by SignTransfer.
+func NewTransferAssignment(pubKey, sig []byte, fields TransferFields) (*TransferCircuit, error) {
+	elements, err := fields.elements()
+	if err != nil {
+		return nil, err
+	}
+	assignment := &TransferCircuit{
+		Sender:   elements[0],
+		Receiver: elements[1],
+		Amount:   elements[2],
+		Nonce:    elements[3],
+	}
+	if err := assignEncodings(twistededwards.BN254, &assignment.PublicKey, &assignment.Signature, pubKey, sig); err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
+
+// elements returns the fields in the order they are hashed, checking that
+// the account identifiers are field elements.
+func (f TransferFields) elements() ([]*big.Int, error) {
+	modulus := ecc.BN254.ScalarField()
+	names := []string{"sender", "receiver"}
+	for i, v := range []*big.Int{f.Sender, f.Receiver} {
+		if v == nil || v.Sign() < 0 || v.Cmp(modulus) >= 0 {
+			return nil, fmt.Errorf("%s must be a field element", names[i])
+		}
+	}
+	return []*big.Int{
+		f.Sender,
+		f.Receiver,
+		new(big.Int).SetUint64(f.Amount),
+		new(big.Int).SetUint64(f.Nonce),
+	}, nil
+}
+
+// Define implements the circuit for EdDSA verification of a signed transfer
+func (circuit *TransferCircuit) Define(api frontend.API) error {
+	curve, err := tedwards.NewEdCurve(api, twistededwards.BN254)
+	if err != nil {
+		return err
+	}
+	hash, err := newMiMCFieldHasher(api)
+	if err != nil {
+		return err
+	}
+	tag, err := DomainTag(transferDomain)
+	if err != nil {
+		return err
+	}
+
+	// The domain tag is absorbed first, then the transfer fields in a fixed
+	// order
+	fields := []frontend.Variable{tag, circuit.Sender, circuit.Receiver, circuit.Amount, circuit.Nonce}
+	return verifyMessageSignature(curve, hash, circuit.PublicKey, circuit.Signature, fields)
+}
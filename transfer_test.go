@@ -0,0 +1,83 @@
+package main
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	cryptoeddsa "github.com/consensys/gnark-crypto/signature/eddsa"
+	"github.com/consensys/gnark/test"
+)
+
+func TestTransferCircuit(t *testing.T) {
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal("Error creating private key:", err)
+	}
+	pubKey := privateKey.Public().Bytes()
+
+	fields := TransferFields{
+		Sender:   big.NewInt(3),
+		Receiver: big.NewInt(7),
+		Amount:   250_000_000,
+		Nonce:    12,
+	}
+	sig, err := SignTransfer(privateKey, fields)
+	if err != nil {
+		t.Fatal("Error signing transfer:", err)
+	}
+	assignment, err := NewTransferAssignment(pubKey, sig, fields)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+
+	assert := test.NewAssert(t)
+	assert.SolvingSucceeded(&TransferCircuit{}, assignment, test.WithCurves(ecc.BN254))
+
+	// The same signature must not verify for a tampered amount
+	tampered := fields
+	tampered.Amount++
+	tamperedAssignment, err := NewTransferAssignment(pubKey, sig, tampered)
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+	assert.SolvingFailed(&TransferCircuit{}, tamperedAssignment, test.WithCurves(ecc.BN254))
+}
+
+func TestTransferRejectsPaymentSignature(t *testing.T) {
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal("Error creating private key:", err)
+	}
+	pubKey := privateKey.Public().Bytes()
+	const nonce, amount = 12, 250_000_000
+	sig, err := SignPayment(privateKey, nonce, amount)
+	if err != nil {
+		t.Fatal("Error signing payment:", err)
+	}
+
+	// A payment hashes the same field elements as a transfer from X to Y
+	// of the signer's key, so only the domain tags tell them apart
+	xy, err := PointMessage(twistededwards.BN254, pubKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assignment, err := NewTransferAssignment(pubKey, sig, TransferFields{Sender: xy[0], Receiver: xy[1], Amount: nonce, Nonce: amount})
+	if err != nil {
+		t.Fatal("Error creating assignment:", err)
+	}
+	assert := test.NewAssert(t)
+	assert.SolvingFailed(&TransferCircuit{}, assignment, test.WithCurves(ecc.BN254))
+}
+
+func TestHashTransferRejectsOutOfFieldAccounts(t *testing.T) {
+	fields := TransferFields{Sender: big.NewInt(3), Receiver: ecc.BN254.ScalarField()}
+	if _, err := HashTransfer(fields); err == nil {
+		t.Fatal("Expected an error for a receiver equal to the modulus")
+	}
+	if _, err := HashTransfer(TransferFields{Receiver: big.NewInt(7)}); err == nil {
+		t.Fatal("Expected an error for a missing sender")
+	}
+}
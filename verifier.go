@@ -0,0 +1,261 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark-crypto/signature"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+)
+
+// ErrVerifierClosed is returned by the methods of a Verifier after Close.
+var ErrVerifierClosed = errors.New("verifier is closed")
+
+// Verifier holds a compiled EdDSA circuit and the keys of its Backend so
+// that many signatures can be proven and verified without paying for Compile
+// and Setup each time. Its fields are never modified after NewVerifier
+// returns, except for the keys Close releases, so Prove and Verify may be
+// called repeatedly and concurrently.
+type Verifier struct {
+	curve            ecc.ID
+	inner            twistededwards.ID
+	nbLimbs          int
+	mimcKey          []byte
+	rejectNeutralKey bool
+	metrics          *Metrics
+	backend          Backend
+	keys             atomic.Pointer[verifierKeys]
+}
+
+// verifierKeys are the compiled circuit and the backend keys of a Verifier,
+// released together by Close.
+type verifierKeys struct {
+	ccs constraint.ConstraintSystem
+	pk  ProvingKey
+	vk  VerifyingKey
+}
+
+// VerifierConfig customizes the circuit a Verifier compiles.
+type VerifierConfig struct {
+	// MiMCKey, if set, keys the MiMC hash of the circuit. See
+	// EdDSACircuit.MiMCKey.
+	MiMCKey []byte
+
+	// RejectNeutralKey rejects the neutral element as a public key, both
+	// before proving and verifying and in the circuit. See
+	// ErrNeutralPublicKey.
+	RejectNeutralKey bool
+
+	// UseCommitment compiles the circuit with EdDSACircuit.UseCommitment,
+	// which makes it smaller at the price of a Pedersen commitment in the
+	// Groth16 keys and proofs.
+	UseCommitment bool
+
+	// Metrics, if set, is updated by every Prove and Verify. It may be
+	// shared between verifiers to aggregate their counts.
+	Metrics *Metrics
+
+	// Backend proves and verifies, Groth16Backend if nil.
+	Backend Backend
+}
+
+// NewVerifier compiles the EdDSA circuit for curve and runs the Groth16
+// setup once. Keys and signatures must be on InnerCurve(curve).
+func NewVerifier(curve ecc.ID) (*Verifier, error) {
+	return NewVerifierWithConfig(curve, VerifierConfig{})
+}
+
+// NewVerifierWithConfig is NewVerifier for the circuit described by cfg.
+func NewVerifierWithConfig(curve ecc.ID, cfg VerifierConfig) (*Verifier, error) {
+	inner, err := InnerCurve(curve)
+	if err != nil {
+		return nil, err
+	}
+
+	b := cfg.Backend
+	if b == nil {
+		b = Groth16Backend{}
+	}
+	circuit := NewEdDSACircuit(DefaultMessageLimbs)
+	circuit.MiMCKey = cfg.MiMCKey
+	circuit.RejectNeutralKey = cfg.RejectNeutralKey
+	circuit.UseCommitment = cfg.UseCommitment
+	ccs, err := compileCircuit(curve, b.ID(), circuit)
+	if err != nil {
+		return nil, err
+	}
+	pk, vk, err := b.Setup(ccs)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &Verifier{
+		curve:            curve,
+		inner:            inner,
+		nbLimbs:          DefaultMessageLimbs,
+		mimcKey:          slices.Clone(cfg.MiMCKey),
+		rejectNeutralKey: cfg.RejectNeutralKey,
+		metrics:          cfg.Metrics,
+		backend:          b,
+	}
+	v.keys.Store(&verifierKeys{ccs: ccs, pk: pk, vk: vk})
+	return v, nil
+}
+
+// Prove proves that sig is a valid signature of msg under pubKey. Ed25519
+// signatures are rejected with ErrEd25519Signature.
+func (v *Verifier) Prove(pubKey, sig, msg []byte) (Proof, error) {
+	start := time.Now()
+	proof, err := v.prove(pubKey, sig, msg)
+	v.metrics.observeProve(start, err)
+	return proof, err
+}
+
+func (v *Verifier) prove(pubKey, sig, msg []byte) (Proof, error) {
+	if err := CheckNotEd25519(pubKey, sig, msg); err != nil {
+		return nil, err
+	}
+	if err := v.checkInputs(pubKey, sig); err != nil {
+		return nil, err
+	}
+	// Checking natively first is cheap next to proving, and names the hash
+	// of a signature made for another circuit. Keyed MiMC has no
+	// alternative to suggest, so it is left to the prover.
+	if len(v.mimcKey) == 0 {
+		if err := CheckSignatureHash(v.inner, HashMiMC, pubKey, sig, msg, v.nbLimbs); err != nil {
+			return nil, err
+		}
+	}
+	keys, err := v.loadKeys()
+	if err != nil {
+		return nil, err
+	}
+	assignment, err := NewAssignmentOn(v.inner, pubKey, sig, msg, v.nbLimbs)
+	if err != nil {
+		return nil, err
+	}
+	return v.backend.Prove(keys.ccs, keys.pk, assignment)
+}
+
+// SignAndProve signs msg with priv, a private key on the curve of v, under
+// the hash the circuit of v recomputes, and proves the signature with v. It
+// returns the proof together with the signature, which the verifier needs
+// as a public input along with the public key of priv and msg.
+func SignAndProve(priv signature.Signer, msg []byte, v *Verifier) (Proof, []byte, error) {
+	var sig []byte
+	var err error
+	if len(v.mimcKey) > 0 {
+		sig, err = SignMessageKeyed(v.inner, v.mimcKey, priv, msg, v.nbLimbs)
+	} else {
+		sig, err = SignMessageOn(v.inner, HashMiMC, priv, msg, v.nbLimbs)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("signing message: %w", err)
+	}
+	proof, err := v.Prove(priv.Public().Bytes(), sig, msg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return proof, sig, nil
+}
+
+// Verify checks proof against the public inputs pubKey, sig and msg.
+func (v *Verifier) Verify(proof Proof, pubKey, sig, msg []byte) error {
+	start := time.Now()
+	err := v.verify(proof, pubKey, sig, msg)
+	v.metrics.observeVerify(start, err)
+	return err
+}
+
+func (v *Verifier) verify(proof Proof, pubKey, sig, msg []byte) error {
+	if err := CheckNotEd25519(pubKey, sig, msg); err != nil {
+		return err
+	}
+	keys, err := v.loadKeys()
+	if err != nil {
+		return err
+	}
+	if err := v.checkInputs(pubKey, sig); err != nil {
+		return err
+	}
+	publicWitness, err := newPublicWitness(v.curve, pubKey, sig, msg)
+	if err != nil {
+		return err
+	}
+	return v.backend.Verify(keys.vk, proof, publicWitness)
+}
+
+// Statement is the public witness for fixed public inputs, built once by
+// NewStatement so that proofs of the same statement can be verified without
+// rebuilding it each time. It is never modified, so it may be shared between
+// goroutines.
+type Statement struct {
+	publicWitness witness.Witness
+}
+
+// NewStatement builds the public witness for pubKey, sig and msg.
+func (v *Verifier) NewStatement(pubKey, sig, msg []byte) (*Statement, error) {
+	if err := v.checkInputs(pubKey, sig); err != nil {
+		return nil, err
+	}
+	publicWitness, err := newPublicWitness(v.curve, pubKey, sig, msg)
+	if err != nil {
+		return nil, err
+	}
+	return &Statement{publicWitness: publicWitness}, nil
+}
+
+// VerifyStatement is Verify against the public inputs of st.
+func (v *Verifier) VerifyStatement(proof Proof, st *Statement) error {
+	start := time.Now()
+	keys, err := v.loadKeys()
+	if err == nil {
+		err = v.backend.Verify(keys.vk, proof, st.publicWitness)
+	}
+	v.metrics.observeVerify(start, err)
+	return err
+}
+
+// Close releases the compiled circuit and the keys of v, so that their
+// memory can be reclaimed once the proofs and verifications in progress
+// complete. Prove, Verify and VerifyStatement then fail with
+// ErrVerifierClosed. Close may be called more than once.
+func (v *Verifier) Close() {
+	v.keys.Store(nil)
+}
+
+// loadKeys returns the keys of v, or ErrVerifierClosed after Close.
+func (v *Verifier) loadKeys() (*verifierKeys, error) {
+	keys := v.keys.Load()
+	if keys == nil {
+		return nil, ErrVerifierClosed
+	}
+	return keys, nil
+}
+
+// checkInputs rejects public keys outside the prime-order subgroup, the
+// neutral public key if so configured, and non-canonical signatures before
+// any witness is built from them.
+func (v *Verifier) checkInputs(pubKey, sig []byte) error {
+	if err := ValidatePublicKey(v.inner, pubKey); err != nil {
+		return fmt.Errorf("invalid public key: %w", err)
+	}
+	if v.rejectNeutralKey {
+		if err := CheckPublicKeyNotNeutral(v.inner, pubKey); err != nil {
+			return fmt.Errorf("invalid public key: %w", err)
+		}
+	}
+	if err := checkEncodingSizes(v.inner, pubKey, sig); err != nil {
+		return err
+	}
+	if !IsCanonicalSignature(v.inner, sig) {
+		return errors.New("invalid signature: S is not below the subgroup order")
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	edwardsbn254 "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	cryptoeddsa "github.com/consensys/gnark-crypto/signature/eddsa"
+)
+
+func TestVerifier(t *testing.T) {
+	v, err := NewVerifier(ecc.BN254)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The same verifier must be reusable for several signatures
+	for i := 0; i < 2; i++ {
+		privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pubKey := privateKey.Public().Bytes()
+		msg := []byte{0xde, 0xad, 0xf0, byte(i)}
+		sig, err := SignMessage(privateKey, msg, DefaultMessageLimbs)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		proof, err := v.Prove(pubKey, sig, msg)
+		if err != nil {
+			t.Fatal("Error proving:", err)
+		}
+		if err := v.Verify(proof, pubKey, sig, msg); err != nil {
+			t.Fatal("Error verifying:", err)
+		}
+
+		// The proof must not verify against different public inputs
+		if err := v.Verify(proof, pubKey, sig, []byte{0xba, 0xd0}); err == nil {
+			t.Fatal("Expected verification against another message to fail")
+		}
+
+		// Nor against the same signature with S increased by the order
+		params := edwardsbn254.GetEdwardsCurve()
+		s := new(big.Int).SetBytes(sig[32:])
+		s.Add(s, &params.Order)
+		malleable := append(append([]byte(nil), sig[:32]...), s.FillBytes(make([]byte, 32))...)
+		if _, err := v.Prove(pubKey, malleable, msg); err == nil {
+			t.Fatal("Expected proving a non-canonical signature to fail")
+		}
+		if err := v.Verify(proof, pubKey, malleable, msg); err == nil {
+			t.Fatal("Expected verification against a non-canonical signature to fail")
+		}
+	}
+}
+
+func TestVerifierMiMCKey(t *testing.T) {
+	key := []byte("deployment key")
+	v, err := NewVerifierWithConfig(ecc.BN254, VerifierConfig{MiMCKey: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey := privateKey.Public().Bytes()
+	msg := []byte{0xde, 0xad, 0xf0, 0x0d}
+
+	sig, err := SignMessageKeyed(twistededwards.BN254, key, privateKey, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := v.Prove(pubKey, sig, msg)
+	if err != nil {
+		t.Fatal("Error proving:", err)
+	}
+	if err := v.Verify(proof, pubKey, sig, msg); err != nil {
+		t.Fatal("Error verifying:", err)
+	}
+
+	// Signatures under another key, or under unkeyed MiMC, must not prove
+	otherSig, err := SignMessageKeyed(twistededwards.BN254, []byte("another key"), privateKey, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unkeyedSig, err := SignMessage(privateKey, msg, DefaultMessageLimbs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range [][]byte{otherSig, unkeyedSig} {
+		if _, err := v.Prove(pubKey, s, msg); !errors.Is(err, ErrSignatureInvalid) {
+			t.Fatal("Expected ErrSignatureInvalid, got:", err)
+		}
+	}
+}
+
+func TestVerifierStatement(t *testing.T) {
+	v, pubKey, sig, msg, proof := newProvedVerifier(t)
+
+	st, err := v.NewStatement(pubKey, sig, msg)
+	if err != nil {
+		t.Fatal("Error building statement:", err)
+	}
+	// The statement is reusable
+	for i := 0; i < 2; i++ {
+		if err := v.VerifyStatement(proof, st); err != nil {
+			t.Fatal("Error verifying:", err)
+		}
+	}
+
+	other, err := v.NewStatement(pubKey, sig, []byte{0xba, 0xd0})
+	if err != nil {
+		t.Fatal("Error building statement:", err)
+	}
+	if err := v.VerifyStatement(proof, other); err == nil {
+		t.Fatal("Expected verification against another message to fail")
+	}
+}
+
+// BenchmarkVerifierStatement compares verifying the same statement with
+// Verify, which rebuilds the public witness on every call, and with a
+// Statement built once.
+func BenchmarkVerifierStatement(b *testing.B) {
+	v, pubKey, sig, msg, proof := newProvedVerifier(b)
+
+	b.Run("rebuild", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if err := v.Verify(proof, pubKey, sig, msg); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("statement", func(b *testing.B) {
+		st, err := v.NewStatement(pubKey, sig, msg)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if err := v.VerifyStatement(proof, st); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// newProvedVerifier returns a BN254 Verifier together with a signature of a
+// fixed message under a fresh key and a proof for it.
+func newProvedVerifier(tb testing.TB) (v *Verifier, pubKey, sig, msg []byte, proof Proof) {
+	tb.Helper()
+
+	v, err := NewVerifier(ecc.BN254)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	pubKey = privateKey.Public().Bytes()
+	msg = []byte{0xde, 0xad, 0xf0, 0x0d}
+	sig, err = SignMessage(privateKey, msg, DefaultMessageLimbs)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	proof, err = v.Prove(pubKey, sig, msg)
+	if err != nil {
+		tb.Fatal("Error proving:", err)
+	}
+	return v, pubKey, sig, msg, proof
+}
+
+func TestSignAndProve(t *testing.T) {
+	key := []byte("deployment key")
+	for name, cfg := range map[string]VerifierConfig{
+		"unkeyed":         {},
+		"keyed":           {MiMCKey: key},
+		"committed":       {UseCommitment: true},
+		"committed plonk": {UseCommitment: true, Backend: PlonkBackend{}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			v, err := NewVerifierWithConfig(ecc.BN254, cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			privateKey, err := cryptoeddsa.New(twistededwards.BN254, rand.Reader)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pubKey := privateKey.Public().Bytes()
+			msg := []byte{0xde, 0xad, 0xf0, 0x0d}
+
+			proof, sig, err := SignAndProve(privateKey, msg, v)
+			if err != nil {
+				t.Fatal("Error signing and proving:", err)
+			}
+			if err := v.Verify(proof, pubKey, sig, msg); err != nil {
+				t.Fatal("Error verifying:", err)
+			}
+		})
+	}
+}
+
+func TestVerifierRejectNeutralKey(t *testing.T) {
+	v, err := NewVerifierWithConfig(ecc.BN254, VerifierConfig{RejectNeutralKey: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey, sig := neutralKeyForgery(t)
+	msg := []byte{0xde, 0xad, 0xf0, 0x0d}
+
+	_, err = v.Prove(pubKey, sig, msg)
+	if !errors.Is(err, ErrNeutralPublicKey) {
+		t.Fatal("Expected ErrNeutralPublicKey, got:", err)
+	}
+	if !strings.Contains(err.Error(), "neutral element") {
+		t.Fatalf("Expected the error to name the neutral element, got %q", err)
+	}
+}
+
+func TestVerifierClose(t *testing.T) {
+	v, pubKey, sig, msg, proof := newProvedVerifier(t)
+	st, err := v.NewStatement(pubKey, sig, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v.Close()
+	if _, err := v.Prove(pubKey, sig, msg); !errors.Is(err, ErrVerifierClosed) {
+		t.Fatal("Expected ErrVerifierClosed proving, got:", err)
+	}
+	if err := v.Verify(proof, pubKey, sig, msg); !errors.Is(err, ErrVerifierClosed) {
+		t.Fatal("Expected ErrVerifierClosed verifying, got:", err)
+	}
+	if err := v.VerifyStatement(proof, st); !errors.Is(err, ErrVerifierClosed) {
+		t.Fatal("Expected ErrVerifierClosed verifying a statement, got:", err)
+	}
+	v.Close()
+}
+
+func TestNewVerifierUnsupportedCurve(t *testing.T) {
+	if _, err := NewVerifier(ecc.BW6_761); err == nil {
+		t.Fatal("Expected an error for an unsupported curve")
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/consensys/gnark/backend/groth16"
+)
+
+// VerifyJob is a single proof to check with VerifyBatch, together with the
+// raw inputs VerifyProof rebuilds its public witness from.
+type VerifyJob struct {
+	Proof     groth16.Proof
+	PublicKey []byte
+	Signature []byte
+	Message   []byte
+}
+
+// VerifyBatch verifies jobs against vk with one worker per CPU. See
+// VerifyBatchWithWorkers.
+func VerifyBatch(vk groth16.VerifyingKey, jobs []VerifyJob) []error {
+	return VerifyBatchWithWorkers(vk, jobs, runtime.NumCPU())
+}
+
+// VerifyBatchWithWorkers verifies jobs against vk on a pool of workers
+// goroutines and returns one error per job, nil for the proofs that verify.
+// groth16.Verify only reads the verifying key, so the workers share vk; it
+// must not be modified, for instance by ExportSolidityVerifier, until
+// VerifyBatchWithWorkers returns.
+func VerifyBatchWithWorkers(vk groth16.VerifyingKey, jobs []VerifyJob, workers int) []error {
+	workers = max(1, min(workers, len(jobs)))
+	errs := make([]error, len(jobs))
+
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				job := jobs[i]
+				errs[i] = VerifyProof(vk, job.Proof, job.PublicKey, job.Signature, job.Message)
+			}
+		}()
+	}
+	for i := range jobs {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+	return errs
+}
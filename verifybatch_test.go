@@ -0,0 +1,84 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+)
+
+// proveBatch proves n fresh signatures with a single Groth16 setup and
+// returns the verifying key with one job per proof.
+func proveBatch(tb testing.TB, n int) (groth16.VerifyingKey, []VerifyJob) {
+	tb.Helper()
+	pubKeys, sigs, msgs := signBatch(tb, n)
+
+	ccs, err := CompileCircuit(ecc.BN254, backend.GROTH16)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	pk, vk, err := SetupGroth16(ccs)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	jobs := make([]VerifyJob, n)
+	for i := range jobs {
+		assignment, err := NewAssignment(pubKeys[i], sigs[i], msgs[i], DefaultMessageLimbs)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		proof, err := ProveWithGroth16(ccs, pk, assignment)
+		if err != nil {
+			tb.Fatal("Error proving:", err)
+		}
+		jobs[i] = VerifyJob{Proof: proof, PublicKey: pubKeys[i], Signature: sigs[i], Message: msgs[i]}
+	}
+	return vk, jobs
+}
+
+func TestVerifyBatch(t *testing.T) {
+	vk, jobs := proveBatch(t, 4)
+
+	// Check the second proof against another message
+	jobs[1].Message = []byte{0xba, 0xd0}
+
+	errs := VerifyBatchWithWorkers(vk, jobs, 2)
+	if len(errs) != len(jobs) {
+		t.Fatalf("Expected %d errors, got %d", len(jobs), len(errs))
+	}
+	for i, err := range errs {
+		if i == 1 {
+			if err == nil {
+				t.Fatal("Expected verification against another message to fail")
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Error verifying proof %d: %v", i, err)
+		}
+	}
+}
+
+// BenchmarkVerifyBatch compares verifying 100 proofs serially with
+// verifying them on a worker pool.
+func BenchmarkVerifyBatch(b *testing.B) {
+	vk, jobs := proveBatch(b, 100)
+
+	run := func(b *testing.B, verify func() []error) {
+		for i := 0; i < b.N; i++ {
+			for _, err := range verify() {
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	}
+	b.Run("serial", func(b *testing.B) {
+		run(b, func() []error { return VerifyBatchWithWorkers(vk, jobs, 1) })
+	})
+	b.Run("parallel", func(b *testing.B) {
+		run(b, func() []error { return VerifyBatch(vk, jobs) })
+	})
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark/backend/groth16"
+)
+
+// Reasons VerifyDetailed rejects a proof for, wrapped around the underlying
+// error so that callers can tell them apart with errors.Is.
+var (
+	// ErrPublicInputMismatch means the public inputs are invalid or do not
+	// have the shape of the circuit vk was set up for, such as a public key
+	// off the curve or a verifying key for a circuit with another number of
+	// message limbs. No proof can verify against them.
+	ErrPublicInputMismatch = errors.New("public inputs do not match the circuit")
+
+	// ErrMalformedProof means the proof is not a valid Groth16 proof for the
+	// curve of vk, for instance a proof for another curve or with points
+	// outside the prime-order subgroups.
+	ErrMalformedProof = errors.New("proof is malformed")
+
+	// ErrVerificationFailed means the pairing check failed: a well-formed
+	// proof does not prove these well-formed inputs. A genuine proof of other
+	// inputs and a forged proof are indistinguishable at this point.
+	ErrVerificationFailed = errors.New("proof does not verify")
+)
+
+// VerifyDetailed is VerifyProof classifying failures as ErrPublicInputMismatch,
+// ErrMalformedProof or ErrVerificationFailed. The inputs are validated as a
+// Verifier does before any pairing is computed, so a failure that is not an
+// input or proof encoding problem is reported as ErrVerificationFailed.
+func VerifyDetailed(vk groth16.VerifyingKey, proof groth16.Proof, pubKey, sig, msg []byte) error {
+	inner, err := InnerCurve(vk.CurveID())
+	if err != nil {
+		return err
+	}
+	if err := ValidatePublicKey(inner, pubKey); err != nil {
+		return fmt.Errorf("%w: invalid public key: %w", ErrPublicInputMismatch, err)
+	}
+	if err := checkEncodingSizes(inner, pubKey, sig); err != nil {
+		return fmt.Errorf("%w: %w", ErrPublicInputMismatch, err)
+	}
+	if !IsCanonicalSignature(inner, sig) {
+		return fmt.Errorf("%w: invalid signature: S is not below the subgroup order", ErrPublicInputMismatch)
+	}
+	publicWitness, err := newPublicWitness(vk.CurveID(), pubKey, sig, msg)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrPublicInputMismatch, err)
+	}
+	elements, err := witnessElements(publicWitness)
+	if err != nil {
+		return err
+	}
+	if got, want := len(elements), vk.NbPublicWitness(); got != want {
+		return fmt.Errorf("%w: the verifying key expects %d public inputs, got %d", ErrPublicInputMismatch, want, got)
+	}
+
+	if err := checkProofEncoding(vk, proof); err != nil {
+		return fmt.Errorf("%w: %w", ErrMalformedProof, err)
+	}
+	if err := verifyPublicWitness(vk, proof, publicWitness); err != nil {
+		if errors.Is(err, ErrProverPanic) {
+			return fmt.Errorf("%w: %w", ErrMalformedProof, err)
+		}
+		return fmt.Errorf("%w: %w", ErrVerificationFailed, err)
+	}
+	return nil
+}
+
+// checkProofEncoding checks that proof is for the curve of vk and decodes
+// back from its uncompressed encoding, which rejects points outside the
+// prime-order subgroups.
+func checkProofEncoding(vk groth16.VerifyingKey, proof groth16.Proof) error {
+	if proof == nil {
+		return errors.New("no proof")
+	}
+	if proof.CurveID() != vk.CurveID() {
+		return fmt.Errorf("proof is for %s, the verifying key for %s", curveName(proof.CurveID()), curveName(vk.CurveID()))
+	}
+	var buf bytes.Buffer
+	if _, err := proof.WriteRawTo(&buf); err != nil {
+		return fmt.Errorf("serializing proof: %w", err)
+	}
+	return readFrom("proof", &buf, groth16.NewProof(vk.CurveID()))
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+)
+
+func TestVerifyDetailed(t *testing.T) {
+	v, pubKey, sig, msg, p := newProvedVerifier(t)
+	vk := v.keys.Load().vk.(groth16.VerifyingKey)
+	proof := p.(groth16.Proof)
+	if err := VerifyDetailed(vk, proof, pubKey, sig, msg); err != nil {
+		t.Fatal("Error verifying:", err)
+	}
+
+	// A verifying key for a circuit with fewer message limbs
+	smallCCS, err := compileCircuit(ecc.BN254, backend.GROTH16, NewEdDSACircuit(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, smallVK, err := SetupGroth16(smallCCS)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name             string
+		vk               groth16.VerifyingKey
+		proof            groth16.Proof
+		pubKey, sig, msg []byte
+		want             error
+	}{
+		{"low order public key", vk, proof, lowOrderPoint(), sig, msg, ErrPublicInputMismatch},
+		{"message too long", vk, proof, pubKey, sig, make([]byte, DefaultMessageLimbs*MessageLimbSize+1), ErrPublicInputMismatch},
+		{"other circuit", smallVK, proof, pubKey, sig, msg, ErrPublicInputMismatch},
+		{"proof for another curve", vk, groth16.NewProof(ecc.BLS12_381), pubKey, sig, msg, ErrMalformedProof},
+		{"other message", vk, proof, pubKey, sig, []byte{0xba, 0xd0}, ErrVerificationFailed},
+	} {
+		err := VerifyDetailed(tc.vk, tc.proof, tc.pubKey, tc.sig, tc.msg)
+		if !errors.Is(err, tc.want) {
+			t.Fatalf("%s: expected %v, got: %v", tc.name, tc.want, err)
+		}
+	}
+}
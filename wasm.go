@@ -0,0 +1,17 @@
+//go:build js && wasm
+
+package main
+
+import "syscall/js"
+
+// main exposes Verify to JavaScript as the global function eddsaVerify and
+// keeps the module running so that it can be called.
+func main() {
+	js.Global().Set("eddsaVerify", js.FuncOf(func(this js.Value, args []js.Value) any {
+		if len(args) != 5 {
+			return "eddsaVerify expects pubKeyHex, sigHex, msgHex, proofB64 and vkB64"
+		}
+		return Verify(args[0].String(), args[1].String(), args[2].String(), args[3].String(), args[4].String())
+	}))
+	select {}
+}